@@ -0,0 +1,353 @@
+package api
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OpenAPISpec is the OpenAPI 3 description of the HTTP API, served at
+// /api/openapi.json and used to generate pkg/client
+//
+//go:embed openapi.json
+var OpenAPISpec []byte
+
+// Schema is the subset of OpenAPI schema objects the spec uses
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties,omitempty"`
+}
+
+// Extra returns the schema for properties not listed in Properties, and
+// whether such properties are allowed at all
+func (s *Schema) Extra() (*Schema, bool) {
+	raw := bytes.TrimSpace(s.AdditionalProperties)
+	switch {
+	case len(raw) == 0:
+		// OpenAPI allows extra properties by default, but our responses
+		// are fully described, so anything undeclared is a spec drift
+		return nil, len(s.Properties) == 0
+	case string(raw) == "true":
+		return nil, true
+	case string(raw) == "false":
+		return nil, false
+	}
+	var extra Schema
+	if err := json.Unmarshal(raw, &extra); err != nil {
+		return nil, true
+	}
+	return &extra, true
+}
+
+// Parameter is a path or query parameter of an operation
+type Parameter struct {
+	Ref         string  `json:"$ref,omitempty"`
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// MediaType is one content type of a request or response body
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Response describes one status code of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Operation is one method on a path
+type Operation struct {
+	OperationID string      `json:"operationId"`
+	Summary     string      `json:"summary"`
+	Description string      `json:"description,omitempty"`
+	Tags        []string    `json:"tags,omitempty"`
+	Deprecated  bool        `json:"deprecated,omitempty"`
+	Parameters  []Parameter `json:"parameters,omitempty"`
+	RequestBody *struct {
+		Required bool                 `json:"required"`
+		Content  map[string]MediaType `json:"content"`
+	} `json:"requestBody,omitempty"`
+	Security  []map[string][]string `json:"security,omitempty"`
+	Responses map[string]Response   `json:"responses"`
+}
+
+// Spec is a parsed OpenAPI document
+type Spec struct {
+	OpenAPI    string                           `json:"openapi"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components struct {
+		Parameters map[string]Parameter `json:"parameters"`
+		Schemas    map[string]*Schema   `json:"schemas"`
+	} `json:"components"`
+}
+
+var (
+	specOnce   sync.Once
+	parsedSpec *Spec
+	specErr    error
+)
+
+// LoadSpec parses OpenAPISpec once
+func LoadSpec() (*Spec, error) {
+	specOnce.Do(func() {
+		parsedSpec, specErr = ParseSpec(OpenAPISpec)
+	})
+	return parsedSpec, specErr
+}
+
+// ParseSpec parses an OpenAPI document and resolves parameter references
+func ParseSpec(data []byte) (*Spec, error) {
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("error parsing OpenAPI spec: %w", err)
+	}
+
+	for path, methods := range spec.Paths {
+		for method, op := range methods {
+			for i, param := range op.Parameters {
+				if param.Ref == "" {
+					continue
+				}
+				resolved, ok := spec.Components.Parameters[strings.TrimPrefix(param.Ref, "#/components/parameters/")]
+				if !ok {
+					return nil, fmt.Errorf("%s %s: unknown parameter %s", method, path, param.Ref)
+				}
+				op.Parameters[i] = resolved
+			}
+		}
+	}
+	return &spec, nil
+}
+
+// Resolve follows a $ref to a component schema
+func (spec *Spec) Resolve(schema *Schema) (*Schema, error) {
+	if schema == nil || schema.Ref == "" {
+		return schema, nil
+	}
+	name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+	resolved, ok := spec.Components.Schemas[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema %s", schema.Ref)
+	}
+	return resolved, nil
+}
+
+// Operation finds the operation serving method and a concrete request path
+// such as /api/matches/42, returning the matched path template. As with
+// ServeMux, a literal path wins over a template matching it.
+func (spec *Spec) Operation(method, path string) (string, *Operation, bool) {
+	method = strings.ToLower(method)
+	if op, ok := spec.Paths[path][method]; ok {
+		return path, op, true
+	}
+	for template, methods := range spec.Paths {
+		if op, ok := methods[method]; ok && pathMatches(template, path) {
+			return template, op, true
+		}
+	}
+	return "", nil, false
+}
+
+// pathMatches reports whether path fits a template like /api/matches/{id}
+func pathMatches(template, path string) bool {
+	want := strings.Split(strings.Trim(template, "/"), "/")
+	got := strings.Split(strings.Trim(path, "/"), "/")
+	if len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		if strings.HasPrefix(want[i], "{") && got[i] != "" {
+			continue
+		}
+		if want[i] != got[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateResponse checks a response against the spec: the status code must
+// be documented for the operation and JSON bodies must match its schema
+func ValidateResponse(method, path string, status int, contentType string, body []byte) error {
+	spec, err := LoadSpec()
+	if err != nil {
+		return err
+	}
+	return spec.ValidateResponse(method, path, status, contentType, body)
+}
+
+// ValidateResponse is ValidateResponse for an already parsed spec
+func (spec *Spec) ValidateResponse(method, path string, status int, contentType string, body []byte) error {
+	template, op, ok := spec.Operation(method, path)
+	if !ok {
+		return fmt.Errorf("%s %s is not in the spec", method, path)
+	}
+
+	response, ok := op.Responses[fmt.Sprintf("%d", status)]
+	if !ok {
+		return fmt.Errorf("%s %s: status %d is not documented", method, template, status)
+	}
+	if len(response.Content) == 0 {
+		return nil
+	}
+
+	mediaType := strings.TrimSpace(strings.Split(contentType, ";")[0])
+	content, ok := response.Content[mediaType]
+	if !ok {
+		return fmt.Errorf("%s %s: %d response has undocumented content type %q", method, template, status, mediaType)
+	}
+	if mediaType != "application/json" || content.Schema == nil {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("%s %s: invalid JSON body: %w", method, template, err)
+	}
+
+	if errs := spec.Validate(content.Schema, value); len(errs) > 0 {
+		return fmt.Errorf("%s %s: %d response does not match the spec:\n  %s", method, template, status, strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+// Validate checks a value decoded with json.Decoder.UseNumber against a
+// schema, returning one message per mismatch
+func (spec *Spec) Validate(schema *Schema, value interface{}) []string {
+	var errs []string
+	spec.validate("$", schema, value, &errs)
+	return errs
+}
+
+func (spec *Spec) validate(at string, schema *Schema, value interface{}, errs *[]string) {
+	schema, err := spec.Resolve(schema)
+	if err != nil {
+		*errs = append(*errs, fmt.Sprintf("%s: %v", at, err))
+		return
+	}
+	if schema == nil {
+		return
+	}
+
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, at+": "+fmt.Sprintf(format, args...))
+	}
+
+	if value == nil {
+		if !schema.Nullable && schema.Type != "" {
+			fail("null where %s expected", schema.Type)
+		}
+		return
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		fail("%v is not one of %v", value, schema.Enum)
+	}
+
+	switch schema.Type {
+	case "":
+		return
+
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			fail("expected string, got %T", value)
+			return
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				fail("%q is not an RFC 3339 date-time", s)
+			}
+		}
+
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			fail("expected integer, got %T", value)
+			return
+		}
+		if _, err := n.Int64(); err != nil {
+			fail("%s is not an integer", n)
+		}
+
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			fail("expected number, got %T", value)
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("expected boolean, got %T", value)
+		}
+
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			fail("expected array, got %T", value)
+			return
+		}
+		for i, item := range items {
+			spec.validate(fmt.Sprintf("%s[%d]", at, i), schema.Items, item, errs)
+		}
+
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			fail("expected object, got %T", value)
+			return
+		}
+		for _, name := range schema.Required {
+			if _, ok := obj[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+
+		extra, extraAllowed := schema.Extra()
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if property, ok := schema.Properties[name]; ok {
+				spec.validate(at+"."+name, property, obj[name], errs)
+			} else if !extraAllowed {
+				fail("undocumented property %q", name)
+			} else if extra != nil {
+				spec.validate(at+"."+name, extra, obj[name], errs)
+			}
+		}
+
+	default:
+		fail("unsupported schema type %q", schema.Type)
+	}
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
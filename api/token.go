@@ -0,0 +1,123 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TokenInfo is the decoded content of an x-mas token
+type TokenInfo struct {
+	Format    string                 `json:"format"`
+	Header    map[string]interface{} `json:"header,omitempty"`
+	Claims    map[string]interface{} `json:"claims"`
+	IssuedAt  *time.Time             `json:"issuedAt,omitempty"`
+	ExpiresAt *time.Time             `json:"expiresAt,omitempty"`
+}
+
+// Expired reports whether the token carries an expiry that has passed
+func (t *TokenInfo) Expired() bool {
+	return t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt)
+}
+
+// DecodeToken decodes an x-mas token without verifying its signature.
+// Fotmob has used two formats: a standard JWT, and a base64-encoded JSON
+// object with a "body" (request URL and millisecond timestamp "code") and
+// a "signature".
+func DecodeToken(token string) (*TokenInfo, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, fmt.Errorf("token is empty")
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) == 3 {
+		info := &TokenInfo{Format: "jwt"}
+		if err := decodeSegment(parts[0], &info.Header); err != nil {
+			return nil, fmt.Errorf("invalid JWT header: %v", err)
+		}
+		if err := decodeSegment(parts[1], &info.Claims); err != nil {
+			return nil, fmt.Errorf("invalid JWT claims: %v", err)
+		}
+		info.IssuedAt = unixClaim(info.Claims["iat"], time.Second)
+		info.ExpiresAt = unixClaim(info.Claims["exp"], time.Second)
+		return info, nil
+	}
+
+	var signed struct {
+		Body      map[string]interface{} `json:"body"`
+		Signature string                 `json:"signature"`
+	}
+	if err := decodeSegment(token, &signed); err != nil || signed.Body == nil {
+		return nil, fmt.Errorf("token is neither a JWT nor a signed body")
+	}
+
+	return &TokenInfo{
+		Format:   "signed-body",
+		Claims:   signed.Body,
+		IssuedAt: unixClaim(signed.Body["code"], time.Millisecond),
+	}, nil
+}
+
+// StoredTokenStatus returns the token saved by the last refresh and when it
+// was written
+func StoredTokenStatus() (*TokenStatus, error) {
+	tokenFile := filepath.Join("responses", "x-mas-token.txt")
+
+	info, err := os.Stat(tokenFile)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(tokenFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenStatus{
+		Token:     strings.TrimSpace(string(data)),
+		UpdatedAt: info.ModTime(),
+		Timestamp: info.ModTime().Unix(),
+	}, nil
+}
+
+// decodeSegment base64-decodes (URL or standard alphabet, padded or not)
+// and unmarshals the JSON inside
+func decodeSegment(segment string, v interface{}) error {
+	segment = strings.TrimRight(segment, "=")
+
+	var data []byte
+	var err error
+	for _, enc := range []*base64.Encoding{base64.RawURLEncoding, base64.RawStdEncoding} {
+		if data, err = enc.DecodeString(segment); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	// Keep numbers as written so millisecond timestamps print in full
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+func unixClaim(value interface{}, unit time.Duration) *time.Time {
+	number, ok := value.(json.Number)
+	if !ok {
+		return nil
+	}
+	n, err := number.Int64()
+	if err != nil || n <= 0 {
+		return nil
+	}
+	t := time.Unix(0, n*int64(unit))
+	return &t
+}
@@ -0,0 +1,370 @@
+// Command openapi-gen generates the typed Go client in pkg/client from
+// api/openapi.json. Run it with "go generate ./pkg/client" after changing
+// the spec.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/MichaelBabushkin/sammy_po/api"
+)
+
+// initialisms are words Go style writes in capitals
+var initialisms = map[string]bool{
+	"id": true, "url": true, "utc": true, "tbc": true, "api": true, "json": true,
+}
+
+func main() {
+	specPath := flag.String("spec", "api/openapi.json", "OpenAPI spec to read")
+	out := flag.String("out", "pkg/client/client_gen.go", "file to write")
+	pkg := flag.String("package", "client", "package name of the generated file")
+	flag.Parse()
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatalf("Error reading spec: %v", err)
+	}
+	spec, err := api.ParseSpec(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	g := &generator{spec: spec}
+	source, err := g.generate(*pkg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(*out, source, 0644); err != nil {
+		log.Fatalf("Error writing %s: %v", *out, err)
+	}
+}
+
+type generator struct {
+	spec    *api.Spec
+	buf     bytes.Buffer
+	imports map[string]bool
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) generate(pkg string) ([]byte, error) {
+	g.imports = map[string]bool{"context": true}
+
+	g.buf.Reset()
+	g.types()
+	if err := g.operations(); err != nil {
+		return nil, err
+	}
+	generated := g.buf.Bytes()
+
+	var file bytes.Buffer
+	fmt.Fprintf(&file, "// Code generated by openapi-gen from api/openapi.json. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&file, "package %s\n\nimport (\n", pkg)
+	imports := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	for _, path := range imports {
+		fmt.Fprintf(&file, "%q\n", path)
+	}
+	fmt.Fprintf(&file, ")\n\n")
+	file.Write(generated)
+
+	source, err := format.Source(file.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated code does not compile: %v\n%s", err, file.Bytes())
+	}
+	return source, nil
+}
+
+// types emits one Go type per component schema
+func (g *generator) types() {
+	names := make([]string, 0, len(g.spec.Components.Schemas))
+	for name := range g.spec.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		schema := g.spec.Components.Schemas[name]
+		g.comment(name, schema.Description)
+
+		if !isStruct(schema) {
+			g.printf("type %s %s\n\n", name, g.goType(schema, true))
+			continue
+		}
+
+		g.printf("type %s struct {\n", name)
+		g.fields(schema)
+		g.printf("}\n\n")
+	}
+}
+
+func (g *generator) fields(schema *api.Schema) {
+	required := make(map[string]bool)
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	props := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		props = append(props, name)
+	}
+	sort.Strings(props)
+
+	for _, name := range props {
+		tag := name
+		if !required[name] {
+			tag += ",omitempty"
+		}
+		g.printf("%s %s `json:%q`\n", goName(name), g.goType(schema.Properties[name], required[name]), tag)
+	}
+}
+
+func (g *generator) comment(name, description string) {
+	if description == "" {
+		return
+	}
+	g.printf("// %s: %s\n", name, strings.TrimSuffix(description, "."))
+}
+
+// isStruct reports whether a schema becomes a struct rather than a map
+func isStruct(schema *api.Schema) bool {
+	return schema.Type == "object" && len(schema.Properties) > 0
+}
+
+// goType maps a schema to a Go type. Optional and nullable values that
+// would be ambiguous with their zero value become pointers.
+func (g *generator) goType(schema *api.Schema, required bool) string {
+	optional := !required || schema.Nullable
+
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		if target, ok := g.spec.Components.Schemas[name]; ok && isStruct(target) && optional {
+			return "*" + name
+		}
+		return name
+	}
+
+	switch schema.Type {
+	case "string":
+		if schema.Format == "date-time" {
+			g.imports["time"] = true
+			if optional {
+				return "*time.Time"
+			}
+			return "time.Time"
+		}
+		return "string"
+	case "integer":
+		if schema.Nullable {
+			return "*int"
+		}
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + g.goType(schema.Items, true)
+	case "object":
+		if extra, _ := schema.Extra(); extra != nil {
+			return "map[string]" + g.goType(extra, true)
+		}
+		return "map[string]interface{}"
+	}
+	return "interface{}"
+}
+
+type operation struct {
+	path   string
+	method string
+	op     *api.Operation
+}
+
+// operations emits one Client method per JSON operation. Deprecated
+// operations and HTML pages are left out.
+func (g *generator) operations() error {
+	var ops []operation
+	for path, methods := range g.spec.Paths {
+		for method, op := range methods {
+			if op.Deprecated || responseSchema(op) == nil {
+				continue
+			}
+			ops = append(ops, operation{path: path, method: strings.ToUpper(method), op: op})
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].op.OperationID < ops[j].op.OperationID })
+
+	for _, o := range ops {
+		if err := g.operation(o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func responseSchema(op *api.Operation) *api.Schema {
+	response, ok := op.Responses["200"]
+	if !ok {
+		return nil
+	}
+	return response.Content["application/json"].Schema
+}
+
+func (g *generator) operation(o operation) error {
+	name := goName(o.op.OperationID)
+
+	var pathParams, queryParams []api.Parameter
+	for _, param := range o.op.Parameters {
+		switch param.In {
+		case "path":
+			pathParams = append(pathParams, param)
+		case "query":
+			queryParams = append(queryParams, param)
+		default:
+			return fmt.Errorf("%s: unsupported parameter location %q", o.op.OperationID, param.In)
+		}
+	}
+
+	if len(queryParams) > 0 {
+		g.printf("// %sParams are the optional query parameters of %s\n", name, name)
+		g.printf("type %sParams struct {\n", name)
+		for _, param := range queryParams {
+			if param.Description != "" {
+				g.printf("// %s\n", param.Description)
+			}
+			g.printf("%s %s\n", goName(param.Name), g.goType(param.Schema, true))
+		}
+		g.printf("}\n\n")
+	}
+
+	args := []string{"ctx context.Context"}
+	for _, param := range pathParams {
+		args = append(args, fmt.Sprintf("%s %s", param.Name, g.goType(param.Schema, true)))
+	}
+	bodyArg := "nil"
+	if o.op.RequestBody != nil {
+		schema := o.op.RequestBody.Content["application/json"].Schema
+		if schema == nil {
+			return fmt.Errorf("%s: request body is not JSON", o.op.OperationID)
+		}
+		args = append(args, "body "+g.goType(schema, false))
+		bodyArg = "body"
+	}
+	if len(queryParams) > 0 {
+		args = append(args, fmt.Sprintf("params *%sParams", name))
+	}
+
+	result := g.goType(responseSchema(o.op), false)
+	zero := "nil"
+
+	g.printf("// %s: %s\n", name, o.op.Summary)
+	g.printf("//\n// %s %s\n", o.method, o.path)
+	g.printf("func (c *Client) %s(%s) (%s, error) {\n", name, strings.Join(args, ", "), result)
+	g.printf("path := %s\n", g.pathExpr(o.path, pathParams))
+
+	query := "nil"
+	if len(queryParams) > 0 {
+		g.imports["net/url"] = true
+		query = "query"
+		g.printf("query := url.Values{}\nif params != nil {\n")
+		for _, param := range queryParams {
+			field := "params." + goName(param.Name)
+			switch param.Schema.Type {
+			case "integer":
+				g.imports["strconv"] = true
+				g.printf("if %s != 0 {\nquery.Set(%q, strconv.Itoa(%s))\n}\n", field, param.Name, field)
+			default:
+				g.printf("if %s != \"\" {\nquery.Set(%q, %s)\n}\n", field, param.Name, field)
+			}
+		}
+		g.printf("}\n")
+	}
+
+	if strings.HasPrefix(result, "*") {
+		g.printf("var out %s\n", strings.TrimPrefix(result, "*"))
+		g.printf("if err := c.do(ctx, %q, path, %s, %s, &out); err != nil {\nreturn %s, err\n}\n", o.method, query, bodyArg, zero)
+		g.printf("return &out, nil\n}\n\n")
+		return nil
+	}
+
+	g.printf("var out %s\n", result)
+	g.printf("if err := c.do(ctx, %q, path, %s, %s, &out); err != nil {\nreturn %s, err\n}\n", o.method, query, bodyArg, zero)
+	g.printf("return out, nil\n}\n\n")
+	return nil
+}
+
+// pathExpr builds a Go expression for a path template, escaping parameters
+func (g *generator) pathExpr(template string, params []api.Parameter) string {
+	if len(params) == 0 {
+		return fmt.Sprintf("%q", template)
+	}
+
+	types := make(map[string]string)
+	for _, param := range params {
+		types[param.Name] = param.Schema.Type
+	}
+
+	var parts []string
+	rest := template
+	for {
+		start := strings.Index(rest, "{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest, "}")
+		if start > 0 {
+			parts = append(parts, fmt.Sprintf("%q", rest[:start]))
+		}
+		name := rest[start+1 : end]
+		if types[name] == "integer" {
+			g.imports["strconv"] = true
+			parts = append(parts, fmt.Sprintf("strconv.Itoa(%s)", name))
+		} else {
+			g.imports["net/url"] = true
+			parts = append(parts, fmt.Sprintf("url.PathEscape(%s)", name))
+		}
+		rest = rest[end+1:]
+	}
+	if rest != "" {
+		parts = append(parts, fmt.Sprintf("%q", rest))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// goName turns a JSON or operation name like homeTeamId into HomeTeamID
+func goName(name string) string {
+	var words []string
+	start := 0
+	for i := 1; i < len(name); i++ {
+		if isLower(name[i-1]) && isUpper(name[i]) {
+			words = append(words, name[start:i])
+			start = i
+		}
+	}
+	words = append(words, name[start:])
+
+	for i, word := range words {
+		if initialisms[strings.ToLower(word)] {
+			words[i] = strings.ToUpper(word)
+		} else {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return strings.Join(words, "")
+}
+
+func isLower(c byte) bool { return c >= 'a' && c <= 'z' || c >= '0' && c <= '9' }
+func isUpper(c byte) bool { return c >= 'A' && c <= 'Z' }
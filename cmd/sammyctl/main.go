@@ -0,0 +1,119 @@
+// Command sammyctl exposes the server's schedule and token logic on the
+// command line, for scripting without running the web server.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+	"github.com/MichaelBabushkin/sammy_po/pkg/stadium"
+	"github.com/MichaelBabushkin/sammy_po/server"
+	"github.com/joho/godotenv"
+)
+
+const usage = `Usage: sammyctl <command> [flags]
+
+Commands:
+  matches          Print upcoming fixtures at Sammy Ofer Stadium
+  token refresh    Scrape a new x-mas token from Fotmob
+  token inspect    Decode the stored x-mas token and show its age
+  stadium info     Print Sammy Ofer Stadium information
+  serve            Run the HTTP server
+  openapi print    Print the OpenAPI spec
+  openapi check    Validate endpoint responses against the OpenAPI spec
+
+Run "sammyctl <command> -h" for command flags.
+`
+
+func init() {
+	// Load .env file
+	godotenv.Load()
+
+	// Ensure the responses directory exists
+	os.MkdirAll("responses", 0755)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// Ctrl-C cancels in-flight Fotmob requests and scrapes
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var err error
+	switch os.Args[1] {
+	case "matches":
+		err = runMatches(ctx, os.Args[2:])
+	case "token":
+		err = runToken(ctx, os.Args[2:])
+	case "stadium":
+		err = runStadium(os.Args[2:])
+	case "serve":
+		err = runServe(os.Args[2:])
+	case "openapi":
+		err = runOpenAPI(os.Args[2:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+func runStadium(args []string) error {
+	if len(args) == 0 || args[0] != "info" {
+		return fmt.Errorf("usage: sammyctl stadium info [-lang en|he]")
+	}
+
+	fs := flag.NewFlagSet("stadium info", flag.ExitOnError)
+	lang := fs.String("lang", "en", "response language (en or he)")
+	fs.Parse(args[1:])
+
+	locale, ok := i18n.Parse(*lang)
+	if !ok {
+		return fmt.Errorf("unsupported language %q", *lang)
+	}
+
+	store, err := stadium.Open(server.ConfigFromEnv().StadiumFile)
+	if err != nil {
+		return err
+	}
+	profile, ok := store.Get(stadium.SammyOfer)
+	if !ok {
+		profile = stadium.Defaults[0]
+	}
+	return printJSON(profile.Info(locale))
+}
+
+func runServe(args []string) error {
+	config := server.ConfigFromEnv()
+
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	fs.StringVar(&config.Port, "port", config.Port, "port to listen on")
+	fs.StringVar(&config.StaticDir, "frontend-dir", config.StaticDir, "serve the frontend from this directory instead of the embedded build")
+	fs.Parse(args)
+
+	return server.New(config).ListenAndServe()
+}
+
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(v)
+}
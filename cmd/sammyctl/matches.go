@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/fotmob"
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+	"github.com/MichaelBabushkin/sammy_po/pkg/scraper"
+)
+
+func runMatches(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("matches", flag.ExitOnError)
+	format := fs.String("format", "table", "output format: table, json or csv")
+	lang := fs.String("lang", "en", "language for dates and team names (en or he)")
+	noRefresh := fs.Bool("no-refresh", false, "use the stored token even if it is stale")
+	fs.Parse(args)
+
+	locale, ok := i18n.Parse(*lang)
+	if !ok {
+		return fmt.Errorf("unsupported language %q", *lang)
+	}
+
+	if !*noRefresh && !scraper.IsTokenFresh() {
+		fmt.Fprintln(os.Stderr, "Token is stale, refreshing...")
+		scraper.RefreshToken(ctx)
+	}
+
+	rawMatches, err := fotmob.NewFotmobClient().FetchUpcomingSammyOferMatches(ctx, locale)
+	if err != nil {
+		return err
+	}
+
+	matches := make([]fotmob.Match, 0, len(rawMatches))
+	for _, raw := range rawMatches {
+		matchMap, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if match, ok := matchMap["match"].(fotmob.Match); ok {
+			matches = append(matches, match)
+		}
+	}
+
+	switch *format {
+	case "json":
+		return printJSON(matches)
+	case "csv":
+		return printMatchesCSV(matches)
+	case "table":
+		return printMatchesTable(matches, locale)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+}
+
+func printMatchesTable(matches []fotmob.Match, locale i18n.Locale) error {
+	if len(matches) == 0 {
+		fmt.Println("No upcoming matches at Sammy Ofer Stadium.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tDATE\tTIME\tHOME\tAWAY\tROUND\tSTATUS")
+	for _, m := range matches {
+		kickoffTime := m.Time
+		if m.TimeTBC {
+			kickoffTime = "TBC"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			m.ID, m.Date, kickoffTime, m.HomeTeam, m.AwayTeam, m.Round, i18n.Status(locale, m.Status))
+	}
+	return w.Flush()
+}
+
+func printMatchesCSV(matches []fotmob.Match) error {
+	w := csv.NewWriter(os.Stdout)
+	w.Write([]string{"id", "kickoff_utc", "kickoff_local", "date", "time", "time_tbc", "home", "away", "competition", "round", "status"})
+
+	for _, m := range matches {
+		kickoffUTC := ""
+		if m.Kickoff != nil {
+			kickoffUTC = m.Kickoff.Format(time.RFC3339)
+		}
+		w.Write([]string{
+			strconv.Itoa(m.ID),
+			kickoffUTC,
+			m.KickoffLocal,
+			m.Date,
+			m.Time,
+			strconv.FormatBool(m.TimeTBC),
+			m.HomeTeam,
+			m.AwayTeam,
+			m.Competition,
+			m.Round,
+			m.Status,
+		})
+	}
+
+	w.Flush()
+	return w.Error()
+}
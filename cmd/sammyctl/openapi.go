@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/api"
+	"github.com/MichaelBabushkin/sammy_po/server"
+)
+
+func runOpenAPI(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: sammyctl openapi <print|check>")
+	}
+
+	switch args[0] {
+	case "print":
+		_, err := os.Stdout.Write(api.OpenAPISpec)
+		return err
+	case "check":
+		return runOpenAPICheck(args[1:])
+	default:
+		return fmt.Errorf("unknown openapi command %q", args[0])
+	}
+}
+
+// runOpenAPICheck calls every read-only endpoint and validates the responses
+// against the spec. Without -url the handlers run in-process, so this works
+// as a check in CI; with -url it checks a deployed server.
+func runOpenAPICheck(args []string) error {
+	fs := flag.NewFlagSet("openapi check", flag.ExitOnError)
+	baseURL := fs.String("url", "", "check a running server instead of the in-process handlers")
+	matchID := fs.Int("match", 0, "match ID for the match endpoints (default: the next fixture)")
+	adminToken := fs.String("admin-token", os.Getenv("ADMIN_TOKEN"), "admin bearer token for the admin endpoints")
+	fs.Parse(args)
+
+	spec, err := api.LoadSpec()
+	if err != nil {
+		return err
+	}
+
+	do := inProcess(server.New(server.ConfigFromEnv()).Handler())
+	if *baseURL != "" {
+		do = remote(strings.TrimSuffix(*baseURL, "/"))
+	}
+
+	failures := 0
+	check := func(method, path string, admin bool) []byte {
+		header := http.Header{}
+		if admin && *adminToken != "" {
+			header.Set("Authorization", "Bearer "+*adminToken)
+		}
+
+		status, contentType, body, err := do(method, path, header)
+		if err != nil {
+			failures++
+			fmt.Printf("FAIL %s %s: %v\n", method, path, err)
+			return nil
+		}
+
+		pathOnly := strings.SplitN(path, "?", 2)[0]
+		if err := spec.ValidateResponse(method, pathOnly, status, contentType, body); err != nil {
+			failures++
+			fmt.Printf("FAIL %s %s (%d): %v\n", method, path, status, err)
+			return nil
+		}
+		fmt.Printf("ok   %s %s (%d)\n", method, path, status)
+		if status != http.StatusOK {
+			return nil
+		}
+		return body
+	}
+
+	check("GET", "/api/health", false)
+	check("GET", "/api/v1/health", false)
+	check("GET", "/api/openapi.json", false)
+	check("GET", "/api/v1/competitions", false)
+	check("GET", "/api/v1/venues/sammyofer/conflicts?window=48h", false)
+	check("GET", "/api/venues/sammyofer/conflicts?lang=he", false)
+	check("GET", "/api/v1/venues/nowhere/conflicts", false)
+	for _, lang := range []string{"en", "he"} {
+		check("GET", "/api/v1/stadium?lang="+lang, false)
+		check("GET", "/api/stadium/sammyofer?lang="+lang, false)
+		check("GET", "/api/v1/stadium/busy?lang="+lang, false)
+		check("GET", "/api/v1/stadium/busy.ics?lang="+lang, false)
+	}
+
+	fixtures := check("GET", "/api/fotmob/sammyofer?lang=he", false)
+	check("GET", "/api/v1/matches?lang=he&perPage=5", false)
+	check("GET", "/api/v1/matches?page=0", false)
+	if *matchID == 0 {
+		*matchID = firstMatchID(fixtures)
+	}
+	if *matchID != 0 {
+		for _, prefix := range []string{"/api/v1", "/api"} {
+			check("GET", fmt.Sprintf("%s/matches/%d", prefix, *matchID), false)
+			check("GET", fmt.Sprintf("%s/matches/%d/h2h?lang=he", prefix, *matchID), false)
+			check("GET", fmt.Sprintf("%s/matches/%d/transit", prefix, *matchID), false)
+		}
+		check("GET", fmt.Sprintf("/matches/%d", *matchID), false)
+	} else {
+		fmt.Println("skip /api/matches/{id}: no upcoming fixture, pass -match")
+	}
+	check("GET", "/api/v1/matches/not-a-number", false)
+	check("GET", "/api/matches/not-a-number", false)
+	check("GET", "/api/v1/matches/999/transit", false)
+
+	for _, path := range []string{"/api/admin/token", "/api/admin/refreshes", "/api/admin/caches", "/api/admin/upstream", "/api/admin/budget", "/api/admin/overrides", "/api/admin/overrides/audit", "/api/admin/notifications", "/api/admin/stadiums", "/api/admin/stadiums/sammyofer", "/api/admin/stadiums/sammyofer/versions", "/api/admin/stadiums/sammyofer/versions/1"} {
+		check("GET", path, true)
+	}
+
+	check("GET", "/schedule?lang=en", false)
+	check("GET", "/stadium", false)
+
+	if failures > 0 {
+		return fmt.Errorf("%d responses do not match the OpenAPI spec", failures)
+	}
+	return nil
+}
+
+type requester func(method, path string, header http.Header) (status int, contentType string, body []byte, err error)
+
+func inProcess(handler http.Handler) requester {
+	return func(method, path string, header http.Header) (int, string, []byte, error) {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header = header
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code, rec.Header().Get("Content-Type"), rec.Body.Bytes(), nil
+	}
+}
+
+func remote(baseURL string) requester {
+	client := &http.Client{Timeout: 2 * time.Minute}
+	return func(method, path string, header http.Header) (int, string, []byte, error) {
+		req, err := http.NewRequest(method, baseURL+path, nil)
+		if err != nil {
+			return 0, "", nil, err
+		}
+		req.Header = header
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, "", nil, err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return resp.StatusCode, resp.Header.Get("Content-Type"), body, err
+	}
+}
+
+// firstMatchID picks the next fixture's ID from the upcoming matches response
+func firstMatchID(body []byte) int {
+	var fixtures []struct {
+		Match struct {
+			ID int `json:"id"`
+		} `json:"match"`
+	}
+	if err := json.Unmarshal(body, &fixtures); err != nil || len(fixtures) == 0 {
+		return 0
+	}
+	return fixtures[0].Match.ID
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/api"
+	"github.com/MichaelBabushkin/sammy_po/pkg/scraper"
+)
+
+func runToken(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: sammyctl token <refresh|inspect>")
+	}
+
+	switch args[0] {
+	case "refresh":
+		return runTokenRefresh(ctx, args[1:])
+	case "inspect":
+		return runTokenInspect(args[1:])
+	default:
+		return fmt.Errorf("unknown token command %q", args[0])
+	}
+}
+
+func runTokenRefresh(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("token refresh", flag.ExitOnError)
+	source := fs.String("source", "", "only use this token source (chromedp, http-fallback or direct-http)")
+	fs.Parse(args)
+
+	if *source != "" {
+		if err := scraper.ForceSource(*source); err != nil {
+			return err
+		}
+	}
+
+	// Same sources as the /api/refresh-token endpoint: browser first, then plain HTTP
+	token, used, err := scraper.Refresh(ctx, "cli")
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Token refreshed via %s: %s\n", used, scraper.TruncateToken(token))
+	return nil
+}
+
+func runTokenInspect(args []string) error {
+	fs := flag.NewFlagSet("token inspect", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the decoded token as JSON")
+	fs.Parse(args)
+
+	status, err := api.StoredTokenStatus()
+	if err != nil {
+		return fmt.Errorf("no stored token: %v", err)
+	}
+
+	info, err := api.DecodeToken(status.Token)
+	if err != nil {
+		return err
+	}
+
+	age := time.Since(status.UpdatedAt).Round(time.Second)
+
+	if *asJSON {
+		return printJSON(map[string]interface{}{
+			"token":     info,
+			"updatedAt": status.UpdatedAt.Format(time.RFC3339),
+			"age":       age.String(),
+			"expired":   info.Expired(),
+		})
+	}
+
+	fmt.Printf("Token:    %s\n", scraper.TruncateToken(status.Token))
+	fmt.Printf("Format:   %s\n", info.Format)
+	fmt.Printf("Stored:   %s (%s ago)\n", status.UpdatedAt.Format(time.RFC3339), age)
+	if info.IssuedAt != nil {
+		fmt.Printf("Issued:   %s\n", info.IssuedAt.Format(time.RFC3339))
+	}
+	if info.ExpiresAt != nil {
+		fmt.Printf("Expires:  %s (expired: %t)\n", info.ExpiresAt.Format(time.RFC3339), info.Expired())
+	}
+
+	keys := make([]string, 0, len(info.Claims))
+	for k := range info.Claims {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fmt.Println("Claims:")
+	for _, k := range keys {
+		fmt.Printf("  %s: %v\n", k, info.Claims[k])
+	}
+	return nil
+}
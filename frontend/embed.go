@@ -0,0 +1,23 @@
+// Package frontend ships the React production build inside the Go binary.
+// The build isn't committed; run go generate here, or npm run build, before
+// building the server.
+package frontend
+
+//go:generate npm run build
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed all:build
+var files embed.FS
+
+// Build returns the contents of the build directory
+func Build() fs.FS {
+	build, err := fs.Sub(files, "build")
+	if err != nil {
+		panic(err)
+	}
+	return build
+}
@@ -0,0 +1,38 @@
+// Writes .gz and .br variants next to compressible build files so the Go
+// server can serve them without compressing on every request.
+const fs = require("fs");
+const path = require("path");
+const zlib = require("zlib");
+
+const buildDir = path.join(__dirname, "..", "build");
+const compressible = /\.(html|js|css|json|svg|txt|ico)$/;
+const minSize = 1024;
+
+function walk(dir) {
+  for (const entry of fs.readdirSync(dir, { withFileTypes: true })) {
+    const file = path.join(dir, entry.name);
+    if (entry.isDirectory()) {
+      walk(file);
+    } else if (compressible.test(entry.name)) {
+      compress(file);
+    }
+  }
+}
+
+function compress(file) {
+  const data = fs.readFileSync(file);
+  if (data.length < minSize) return;
+
+  const gz = zlib.gzipSync(data, { level: 9 });
+  if (gz.length < data.length) fs.writeFileSync(`${file}.gz`, gz);
+
+  const br = zlib.brotliCompressSync(data, {
+    params: {
+      [zlib.constants.BROTLI_PARAM_QUALITY]: 11,
+      [zlib.constants.BROTLI_PARAM_SIZE_HINT]: data.length,
+    },
+  });
+  if (br.length < data.length) fs.writeFileSync(`${file}.br`, br);
+}
+
+walk(buildDir);
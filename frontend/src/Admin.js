@@ -0,0 +1,302 @@
+import React, { useState, useEffect, useCallback } from "react";
+import "./App.css";
+
+const TOKEN_KEY = "sammyAdminToken";
+
+function Admin() {
+  const [adminToken, setAdminToken] = useState(
+    sessionStorage.getItem(TOKEN_KEY) || ""
+  );
+  const [tokenInput, setTokenInput] = useState("");
+  const [data, setData] = useState(null);
+  const [error, setError] = useState(null);
+  const [busy, setBusy] = useState(false);
+
+  const request = useCallback(
+    async (path, options = {}) => {
+      const response = await fetch(path, {
+        ...options,
+        headers: {
+          ...(options.headers || {}),
+          Authorization: `Bearer ${adminToken}`,
+        },
+      });
+      if (response.status === 401) {
+        throw new Error("Invalid admin token");
+      }
+      if (!response.ok) {
+        throw new Error(
+          `HTTP error! Status: ${response.status} ${await response.text()}`
+        );
+      }
+      return response.json();
+    },
+    [adminToken]
+  );
+
+  const fetchData = useCallback(async () => {
+    if (!adminToken) return;
+    try {
+      setBusy(true);
+      const [token, refreshes, caches, upstream] = await Promise.all([
+        request("/api/admin/token"),
+        request("/api/admin/refreshes"),
+        request("/api/admin/caches"),
+        request("/api/admin/upstream"),
+      ]);
+      setData({ token, refreshes, caches, upstream });
+      setError(null);
+    } catch (err) {
+      setError(err.message);
+    } finally {
+      setBusy(false);
+    }
+  }, [adminToken, request]);
+
+  useEffect(() => {
+    fetchData();
+  }, [fetchData]);
+
+  const login = (e) => {
+    e.preventDefault();
+    sessionStorage.setItem(TOKEN_KEY, tokenInput);
+    setAdminToken(tokenInput);
+  };
+
+  const logout = () => {
+    sessionStorage.removeItem(TOKEN_KEY);
+    setAdminToken("");
+    setData(null);
+  };
+
+  const runAction = async (path, options) => {
+    try {
+      setBusy(true);
+      await request(path, options);
+      await fetchData();
+    } catch (err) {
+      setError(err.message);
+      setBusy(false);
+    }
+  };
+
+  const forceSource = (source) =>
+    runAction("/api/admin/token/source", {
+      method: "PUT",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ source }),
+    });
+
+  const purgeCache = (name) =>
+    runAction(
+      name ? `/api/admin/caches/${encodeURIComponent(name)}` : "/api/admin/caches",
+      { method: "DELETE" }
+    );
+
+  const refreshToken = () =>
+    runAction("/api/refresh-token", { method: "POST" });
+
+  if (!adminToken) {
+    return (
+      <div className="App">
+        <header className="header">
+          <h1>Sammy-PO Admin</h1>
+        </header>
+        <form className="admin-login" onSubmit={login}>
+          <input
+            type="password"
+            placeholder="Admin token"
+            value={tokenInput}
+            onChange={(e) => setTokenInput(e.target.value)}
+          />
+          <button type="submit" className="round-btn">
+            Sign in
+          </button>
+        </form>
+      </div>
+    );
+  }
+
+  return (
+    <div className="App">
+      <header className="header">
+        <h1>Sammy-PO Admin</h1>
+        <div className="subtitle">Token, cache and upstream status</div>
+      </header>
+
+      <div className="admin-toolbar">
+        <button className="round-btn" onClick={fetchData} disabled={busy}>
+          Reload
+        </button>
+        <button className="round-btn" onClick={refreshToken} disabled={busy}>
+          Refresh token
+        </button>
+        <button className="round-btn" onClick={logout}>
+          Sign out
+        </button>
+      </div>
+
+      {error && <div className="error">{error}</div>}
+
+      {data && (
+        <>
+          <section className="admin-section">
+            <h2>Token</h2>
+            <table className="admin-table">
+              <tbody>
+                <tr>
+                  <th>Preview</th>
+                  <td>{data.token.tokenPreview || "none"}</td>
+                </tr>
+                <tr>
+                  <th>Stored</th>
+                  <td>
+                    {data.token.updatedAt} ({data.token.age} ago)
+                  </td>
+                </tr>
+                <tr>
+                  <th>Source</th>
+                  <td>{data.token.source || "unknown (stored before startup)"}</td>
+                </tr>
+                <tr>
+                  <th>Expired</th>
+                  <td>{String(data.token.expired)}</td>
+                </tr>
+                <tr>
+                  <th>Claims</th>
+                  <td>
+                    <pre>
+                      {JSON.stringify(data.token.decoded?.claims, null, 2)}
+                    </pre>
+                  </td>
+                </tr>
+                <tr>
+                  <th>Strategy</th>
+                  <td>
+                    <select
+                      value={data.token.forcedSource}
+                      onChange={(e) => forceSource(e.target.value)}
+                      disabled={busy}
+                    >
+                      <option value="">Default order</option>
+                      {data.token.sources.map((source) => (
+                        <option key={source} value={source}>
+                          {source}
+                        </option>
+                      ))}
+                    </select>
+                  </td>
+                </tr>
+              </tbody>
+            </table>
+          </section>
+
+          <section className="admin-section">
+            <h2>Refresh history</h2>
+            <table className="admin-table">
+              <thead>
+                <tr>
+                  <th>Source</th>
+                  <th>Successes</th>
+                  <th>Failures</th>
+                </tr>
+              </thead>
+              <tbody>
+                {Object.entries(data.refreshes.sources).map(([name, stats]) => (
+                  <tr key={name}>
+                    <td>{name}</td>
+                    <td>{stats.successes}</td>
+                    <td>{stats.failures}</td>
+                  </tr>
+                ))}
+              </tbody>
+            </table>
+            <table className="admin-table">
+              <thead>
+                <tr>
+                  <th>Started</th>
+                  <th>Source</th>
+                  <th>Trigger</th>
+                  <th>Duration</th>
+                  <th>Result</th>
+                </tr>
+              </thead>
+              <tbody>
+                {data.refreshes.history.map((attempt, i) => (
+                  <tr key={i}>
+                    <td>{new Date(attempt.startedAt).toLocaleString()}</td>
+                    <td>{attempt.source}</td>
+                    <td>{attempt.trigger}</td>
+                    <td>{attempt.duration}</td>
+                    <td>{attempt.success ? "ok" : attempt.error}</td>
+                  </tr>
+                ))}
+              </tbody>
+            </table>
+          </section>
+
+          <section className="admin-section">
+            <h2>Caches</h2>
+            <button
+              className="round-btn"
+              onClick={() => purgeCache()}
+              disabled={busy}
+            >
+              Purge all
+            </button>
+            {Object.entries(data.caches).map(([name, entries]) => (
+              <div key={name}>
+                <h3>
+                  {name} ({entries.length}){" "}
+                  <button
+                    className="round-btn"
+                    onClick={() => purgeCache(name)}
+                    disabled={busy}
+                  >
+                    Purge
+                  </button>
+                </h3>
+                <table className="admin-table">
+                  <tbody>
+                    {entries.map((entry) => (
+                      <tr key={entry.key}>
+                        <td>{entry.key}</td>
+                        <td>{entry.age} old</td>
+                        <td>{entry.fresh ? "fresh" : "expired"}</td>
+                      </tr>
+                    ))}
+                  </tbody>
+                </table>
+              </div>
+            ))}
+          </section>
+
+          <section className="admin-section">
+            <h2>Upstream</h2>
+            <p>
+              {data.upstream.requests} requests, {data.upstream.errors} errors
+            </p>
+            <table className="admin-table">
+              <tbody>
+                {Object.entries(data.upstream.errorsByKind).map(([kind, n]) => (
+                  <tr key={kind}>
+                    <td>{kind}</td>
+                    <td>{n}</td>
+                  </tr>
+                ))}
+              </tbody>
+            </table>
+            {data.upstream.lastError && (
+              <p>
+                Last error at {data.upstream.lastErrorAt}:{" "}
+                {data.upstream.lastError}
+              </p>
+            )}
+          </section>
+        </>
+      )}
+    </div>
+  );
+}
+
+export default Admin;
@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Entry is a single cached value together with when it was fetched and
+// when it stops being fresh
+type Entry struct {
+	Value     interface{}
+	FetchedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Fresh reports whether the entry is still within its TTL
+func (e *Entry) Fresh() bool {
+	return time.Now().Before(e.ExpiresAt)
+}
+
+// Cache is a small in-memory TTL cache safe for concurrent use
+type Cache struct {
+	mu      sync.RWMutex
+	entries map[string]*Entry
+}
+
+// New creates an empty cache
+func New() *Cache {
+	return &Cache{
+		entries: make(map[string]*Entry),
+	}
+}
+
+// Get returns the cached value for key if it exists and has not expired
+func (c *Cache) Get(key string) (interface{}, bool) {
+	entry, ok := c.GetEntry(key)
+	if !ok || !entry.Fresh() {
+		return nil, false
+	}
+	return entry.Value, true
+}
+
+// GetEntry returns the raw entry for key, even if it has expired
+func (c *Cache) GetEntry(key string) (*Entry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+// Set stores value under key for the given TTL
+func (c *Cache) Set(key string, value interface{}, ttl time.Duration) {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = &Entry{
+		Value:     value,
+		FetchedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+}
+
+// Delete removes key from the cache
+func (c *Cache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
+
+// EntryInfo describes a cached entry without its value
+type EntryInfo struct {
+	Key       string    `json:"key"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Age       string    `json:"age"`
+	Fresh     bool      `json:"fresh"`
+}
+
+// Entries lists every entry, including expired ones, sorted by key
+func (c *Cache) Entries() []EntryInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	infos := make([]EntryInfo, 0, len(c.entries))
+	for key, entry := range c.entries {
+		infos = append(infos, EntryInfo{
+			Key:       key,
+			FetchedAt: entry.FetchedAt,
+			ExpiresAt: entry.ExpiresAt,
+			Age:       now.Sub(entry.FetchedAt).Round(time.Second).String(),
+			Fresh:     entry.Fresh(),
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Key < infos[j].Key })
+	return infos
+}
+
+// Purge removes every entry and returns how many there were
+func (c *Cache) Purge() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := len(c.entries)
+	c.entries = make(map[string]*Entry)
+	return n
+}
@@ -0,0 +1,127 @@
+// Package client is a typed Go client for the Sammy-PO HTTP API. The types
+// and methods in client_gen.go are generated from api/openapi.json; this
+// file holds the hand-written transport they share.
+package client
+
+//go:generate go run ../../cmd/openapi-gen -spec ../../api/openapi.json -out client_gen.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client calls a Sammy-PO server. Admin endpoints need AdminToken, or
+// AdminUser and AdminPassword.
+type Client struct {
+	BaseURL       string
+	HTTPClient    *http.Client
+	AdminToken    string
+	AdminUser     string
+	AdminPassword string
+}
+
+// New returns a client for the server at baseURL, e.g. http://localhost:8000
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+// APIError is returned for non-2xx responses
+type APIError struct {
+	StatusCode int
+	// Code is the stable error code of v1 responses, e.g. "not_found"
+	Code    string
+	Message string
+	// RetryAfter is set for 429 and 503 responses
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("sammy-po API error %d (%s): %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("sammy-po API error %d: %s", e.StatusCode, e.Message)
+}
+
+// do sends a request and decodes a JSON response into out
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error encoding request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.AdminToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AdminToken)
+	} else if c.AdminUser != "" {
+		req.SetBasicAuth(c.AdminUser, c.AdminPassword)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			apiErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		// v1 errors come in an envelope; legacy rate limit responses are
+		// JSON with a message field
+		var envelope struct {
+			Errors  []Error `json:"errors"`
+			Message string  `json:"message"`
+		}
+		if json.Unmarshal(data, &envelope) == nil {
+			if len(envelope.Errors) > 0 {
+				apiErr.Code = envelope.Errors[0].Code
+				apiErr.Message = envelope.Errors[0].Message
+			} else if envelope.Message != "" {
+				apiErr.Message = envelope.Message
+			}
+		}
+		return apiErr
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("error decoding %s %s response: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,1349 @@
+// Code generated by openapi-gen from api/openapi.json. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type AdminToken struct {
+	Age          string         `json:"age,omitempty"`
+	Decoded      *TokenInfo     `json:"decoded,omitempty"`
+	Degraded     DegradedStatus `json:"degraded"`
+	Error        string         `json:"error,omitempty"`
+	Expired      bool           `json:"expired,omitempty"`
+	ForcedSource string         `json:"forcedSource"`
+	Source       string         `json:"source"`
+	Sources      []string       `json:"sources"`
+	TokenPreview string         `json:"tokenPreview,omitempty"`
+	UpdatedAt    *time.Time     `json:"updatedAt,omitempty"`
+}
+
+// BreakerStatus: The Fotmob circuit breaker
+type BreakerStatus struct {
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	OpenedAt            *time.Time `json:"openedAt,omitempty"`
+	ProbeAt             *time.Time `json:"probeAt,omitempty"`
+	ShortCircuited      int        `json:"shortCircuited"`
+	State               string     `json:"state"`
+	Trips               int        `json:"trips"`
+}
+
+type BusyCalendar struct {
+	Address  string       `json:"address"`
+	Capacity int          `json:"capacity"`
+	Venue    string       `json:"venue"`
+	Windows  []BusyWindow `json:"windows"`
+}
+
+type BusyCalendarResponse struct {
+	Data BusyCalendar `json:"data"`
+	Meta Meta         `json:"meta"`
+}
+
+// BusyWindow: When a fixture keeps the streets around the stadium busy, from gates opening until the crowd has cleared
+type BusyWindow struct {
+	Clearance     *time.Time `json:"clearance"`
+	End           time.Time  `json:"end"`
+	ExpectedCrowd int        `json:"expectedCrowd"`
+	GatesOpen     *time.Time `json:"gatesOpen"`
+	Kickoff       *time.Time `json:"kickoff"`
+	Level         string     `json:"level"`
+	Match         Match      `json:"match"`
+	Start         time.Time  `json:"start"`
+	Tentative     bool       `json:"tentative"`
+}
+
+// CacheEntries: Entries of each in-memory cache by cache name
+type CacheEntries map[string][]CacheEntry
+
+type CacheEntry struct {
+	Age       string    `json:"age"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	Fresh     bool      `json:"fresh"`
+	Key       string    `json:"key"`
+}
+
+type CardEvent struct {
+	AddedTime  int    `json:"addedTime,omitempty"`
+	Card       string `json:"card"`
+	IsHome     bool   `json:"isHome"`
+	Minute     int    `json:"minute"`
+	PlayerID   int    `json:"playerId"`
+	PlayerName string `json:"playerName"`
+}
+
+// Competition: A competition a fixture provider carries
+type Competition struct {
+	Country  string `json:"country,omitempty"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+}
+
+type CompetitionListResponse struct {
+	Data []Competition `json:"data"`
+}
+
+// Conflict: Two fixtures at one venue closer together than the window, earlier first
+type Conflict struct {
+	GapMinutes int     `json:"gapMinutes"`
+	Kind       string  `json:"kind"`
+	Matches    []Match `json:"matches"`
+	Venue      string  `json:"venue"`
+}
+
+// Coordinates: WGS 84 position
+type Coordinates struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+type DegradedStatus struct {
+	FailedRefreshes    int        `json:"failedRefreshes"`
+	Mode               string     `json:"mode"`
+	NextRefreshAttempt *time.Time `json:"nextRefreshAttempt,omitempty"`
+	Reason             string     `json:"reason,omitempty"`
+	Refreshing         bool       `json:"refreshing"`
+	Since              *time.Time `json:"since,omitempty"`
+}
+
+// Error: Codes are stable; messages are for humans and may change
+type Error struct {
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	RetryAfter int    `json:"retryAfter,omitempty"`
+}
+
+// ErrorResponse: Envelope of a failed v1 request
+type ErrorResponse struct {
+	Data   interface{} `json:"data"`
+	Errors []Error     `json:"errors"`
+}
+
+// Facility: An accessibility facility, optionally at a gate or stand
+type Facility struct {
+	Description   string `json:"description"`
+	DescriptionHe string `json:"descriptionHe,omitempty"`
+	Gate          string `json:"gate,omitempty"`
+	Stand         string `json:"stand,omitempty"`
+	Type          string `json:"type"`
+}
+
+// Fixture: A raw league fixture in Fotmob's shape, as returned by the fixture provider, annotated with our typed match and localized strings. Other upstream fields pass through unchanged
+type Fixture struct {
+	Away       FixtureTeam    `json:"away"`
+	Home       FixtureTeam    `json:"home"`
+	ID         interface{}    `json:"id,omitempty"`
+	Localized  LocalizedMatch `json:"localized"`
+	Match      Match          `json:"match"`
+	Overridden []string       `json:"overridden,omitempty"`
+	Source     string         `json:"source,omitempty"`
+	Status     *FixtureStatus `json:"status,omitempty"`
+	TimeTS     float64        `json:"timeTS,omitempty"`
+}
+
+// FixtureStatus: Raw Fotmob fixture status
+type FixtureStatus struct {
+	Cancelled bool   `json:"cancelled,omitempty"`
+	Finished  bool   `json:"finished,omitempty"`
+	Started   bool   `json:"started,omitempty"`
+	UTCTime   string `json:"utcTime,omitempty"`
+}
+
+// FixtureTeam: A side of a raw Fotmob fixture
+type FixtureTeam struct {
+	Club      *Team       `json:"club,omitempty"`
+	ID        interface{} `json:"id,omitempty"`
+	Name      string      `json:"name"`
+	ShortName string      `json:"shortName,omitempty"`
+}
+
+// ForceSourceRequest: Token source to force; empty restores the default order
+type ForceSourceRequest struct {
+	Source string `json:"source"`
+}
+
+type ForceSourceResponse struct {
+	ForcedSource string `json:"forcedSource"`
+}
+
+// Gate: An entrance; stands lists the IDs of the stands it leads to
+type Gate struct {
+	Accessible  bool         `json:"accessible"`
+	Coordinates *Coordinates `json:"coordinates,omitempty"`
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	NameHe      string       `json:"nameHe,omitempty"`
+	Stands      []string     `json:"stands"`
+}
+
+type GoalEvent struct {
+	AddedTime  int    `json:"addedTime,omitempty"`
+	AssistName string `json:"assistName,omitempty"`
+	IsHome     bool   `json:"isHome"`
+	Minute     int    `json:"minute"`
+	OwnGoal    bool   `json:"ownGoal"`
+	Penalty    bool   `json:"penalty"`
+	PlayerID   int    `json:"playerId"`
+	PlayerName string `json:"playerName"`
+	ScoreAfter []int  `json:"scoreAfter,omitempty"`
+}
+
+type H2HMeeting struct {
+	AwayScore   int    `json:"awayScore"`
+	AwayTeam    string `json:"awayTeam"`
+	AwayTeamID  string `json:"awayTeamId"`
+	Date        string `json:"date,omitempty"`
+	HomeScore   int    `json:"homeScore"`
+	HomeTeam    string `json:"homeTeam"`
+	HomeTeamID  string `json:"homeTeamId"`
+	MatchID     string `json:"matchId"`
+	Season      string `json:"season"`
+	UTCTime     string `json:"utcTime"`
+	Venue       string `json:"venue,omitempty"`
+	VenueSource string `json:"venueSource"`
+}
+
+type H2HRecord struct {
+	AwayTeamGoals int `json:"awayTeamGoals"`
+	AwayTeamWins  int `json:"awayTeamWins"`
+	Draws         int `json:"draws"`
+	HomeTeamGoals int `json:"homeTeamGoals"`
+	HomeTeamWins  int `json:"homeTeamWins"`
+	Played        int `json:"played"`
+}
+
+type H2HTeam struct {
+	Club *Team  `json:"club,omitempty"`
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// HeadToHead: League meetings between the two clubs over recent seasons
+type HeadToHead struct {
+	AtAwayVenue    H2HRecord       `json:"atAwayVenue"`
+	AtHomeVenue    H2HRecord       `json:"atHomeVenue"`
+	AwayTeam       H2HTeam         `json:"awayTeam"`
+	HomeTeam       H2HTeam         `json:"homeTeam"`
+	Localized      *LocalizedMatch `json:"localized,omitempty"`
+	MatchID        int             `json:"matchId"`
+	RecentMeetings []H2HMeeting    `json:"recentMeetings"`
+	Seasons        []string        `json:"seasons"`
+	SkippedSeasons []string        `json:"skippedSeasons,omitempty"`
+	Totals         H2HRecord       `json:"totals"`
+}
+
+type HeadToHeadResponse struct {
+	Data HeadToHead `json:"data"`
+	Meta Meta       `json:"meta"`
+}
+
+type Health struct {
+	Breaker  BreakerStatus  `json:"breaker"`
+	Degraded DegradedStatus `json:"degraded"`
+	Status   string         `json:"status"`
+}
+
+type HealthResponse struct {
+	Data Health `json:"data"`
+}
+
+type LineupPlayer struct {
+	ID          int    `json:"id"`
+	IsCaptain   bool   `json:"isCaptain,omitempty"`
+	Name        string `json:"name"`
+	PositionID  int    `json:"positionId,omitempty"`
+	ShirtNumber string `json:"shirtNumber,omitempty"`
+}
+
+// LocalizedMatch: Display strings for a match in the requested language
+type LocalizedMatch struct {
+	AwayTeam    string `json:"awayTeam"`
+	Competition string `json:"competition,omitempty"`
+	Date        string `json:"date,omitempty"`
+	HomeTeam    string `json:"homeTeam"`
+	Locale      string `json:"locale"`
+	Status      string `json:"status,omitempty"`
+	Time        string `json:"time,omitempty"`
+}
+
+// Match: Typed view of a fixture; kickoff is the source of truth and date/time are in Asia/Jerusalem
+type Match struct {
+	AwayScore       *int       `json:"awayScore"`
+	AwayTeam        string     `json:"awayTeam"`
+	AwayTeamLogo    string     `json:"awayTeamLogo,omitempty"`
+	Competition     string     `json:"competition"`
+	CompetitionLogo string     `json:"competitionLogo,omitempty"`
+	Date            string     `json:"date"`
+	HomeScore       *int       `json:"homeScore"`
+	HomeTeam        string     `json:"homeTeam"`
+	HomeTeamLogo    string     `json:"homeTeamLogo,omitempty"`
+	ID              int        `json:"id"`
+	Kickoff         *time.Time `json:"kickoff"`
+	KickoffLocal    string     `json:"kickoffLocal,omitempty"`
+	Overridden      []string   `json:"overridden,omitempty"`
+	Round           string     `json:"round"`
+	Source          string     `json:"source"`
+	Status          string     `json:"status"`
+	Time            string     `json:"time"`
+	TimeTBC         bool       `json:"timeTbc"`
+	Venue           string     `json:"venue"`
+	VenueSource     string     `json:"venueSource"`
+}
+
+// MatchDetails: Lineups, events and statistics for a single match
+type MatchDetails struct {
+	Attendance    int                 `json:"attendance,omitempty"`
+	AwayTeam      MatchTeam           `json:"awayTeam"`
+	Cards         []CardEvent         `json:"cards"`
+	Competition   string              `json:"competition"`
+	CompetitionID int                 `json:"competitionId"`
+	Goals         []GoalEvent         `json:"goals"`
+	HomeTeam      MatchTeam           `json:"homeTeam"`
+	ID            int                 `json:"id"`
+	Lineups       MatchLineups        `json:"lineups"`
+	Localized     *LocalizedMatch     `json:"localized,omitempty"`
+	Overridden    []string            `json:"overridden,omitempty"`
+	Referee       string              `json:"referee,omitempty"`
+	Round         string              `json:"round"`
+	Source        string              `json:"source"`
+	Stats         []StatGroup         `json:"stats"`
+	Status        MatchStatus         `json:"status"`
+	Substitutions []SubstitutionEvent `json:"substitutions"`
+	UTCTime       string              `json:"utcTime"`
+	Venue         *MatchVenue         `json:"venue,omitempty"`
+}
+
+type MatchDetailsResponse struct {
+	Data MatchDetails `json:"data"`
+	Meta Meta         `json:"meta"`
+}
+
+type MatchLineups struct {
+	Away *TeamLineup `json:"away,omitempty"`
+	Home *TeamLineup `json:"home,omitempty"`
+}
+
+type MatchListResponse struct {
+	Data []MatchSummary `json:"data"`
+	Meta Meta           `json:"meta"`
+}
+
+type MatchStatus struct {
+	Cancelled bool   `json:"cancelled"`
+	Finished  bool   `json:"finished"`
+	LiveTime  string `json:"liveTime,omitempty"`
+	Long      string `json:"long,omitempty"`
+	Score     string `json:"score,omitempty"`
+	Short     string `json:"short,omitempty"`
+	Started   bool   `json:"started"`
+}
+
+// MatchSummary: An upcoming match: our typed fields plus display strings in the requested language
+type MatchSummary struct {
+	AwayScore       *int           `json:"awayScore"`
+	AwayTeam        string         `json:"awayTeam"`
+	AwayTeamLogo    string         `json:"awayTeamLogo,omitempty"`
+	Competition     string         `json:"competition"`
+	CompetitionLogo string         `json:"competitionLogo,omitempty"`
+	Date            string         `json:"date"`
+	HomeScore       *int           `json:"homeScore"`
+	HomeTeam        string         `json:"homeTeam"`
+	HomeTeamLogo    string         `json:"homeTeamLogo,omitempty"`
+	ID              int            `json:"id"`
+	Kickoff         *time.Time     `json:"kickoff"`
+	KickoffLocal    string         `json:"kickoffLocal,omitempty"`
+	Localized       LocalizedMatch `json:"localized"`
+	Overridden      []string       `json:"overridden,omitempty"`
+	Round           string         `json:"round"`
+	Source          string         `json:"source"`
+	Status          string         `json:"status"`
+	Time            string         `json:"time"`
+	TimeTBC         bool           `json:"timeTbc"`
+	Venue           string         `json:"venue"`
+	VenueSource     string         `json:"venueSource"`
+}
+
+type MatchTeam struct {
+	Club    *Team  `json:"club,omitempty"`
+	ID      int    `json:"id"`
+	LogoURL string `json:"logoUrl,omitempty"`
+	Name    string `json:"name"`
+	Score   *int   `json:"score"`
+}
+
+// MatchTransit: Public transport to and from a match at Sammy Ofer. While the kickoff time is to be confirmed the times are null and only stops are given
+type MatchTransit struct {
+	Arrivals     []TransitTrip `json:"arrivals"`
+	ArriveBy     *time.Time    `json:"arriveBy"`
+	Departures   []TransitTrip `json:"departures"`
+	FeedUpdated  time.Time     `json:"feedUpdated"`
+	FinalWhistle *time.Time    `json:"finalWhistle"`
+	GatesOpen    *time.Time    `json:"gatesOpen"`
+	Match        Match         `json:"match"`
+	Stops        []TransitStop `json:"stops"`
+	Venue        string        `json:"venue"`
+}
+
+type MatchTransitResponse struct {
+	Data MatchTransit `json:"data"`
+	Meta Meta         `json:"meta"`
+}
+
+type MatchVenue struct {
+	Capacity int     `json:"capacity,omitempty"`
+	City     string  `json:"city,omitempty"`
+	Country  string  `json:"country,omitempty"`
+	Lat      float64 `json:"lat,omitempty"`
+	Long     float64 `json:"long,omitempty"`
+	Name     string  `json:"name"`
+}
+
+// Meta: Where the data came from and how fresh it is
+type Meta struct {
+	FetchedAt  *time.Time  `json:"fetchedAt,omitempty"`
+	Locale     string      `json:"locale,omitempty"`
+	MaxAge     int         `json:"maxAge"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+	Source     string      `json:"source"`
+	Stale      bool        `json:"stale"`
+}
+
+// NotificationList: Recent schedule changes, newest first
+type NotificationList struct {
+	Notifications []ScheduleChange `json:"notifications"`
+	Webhook       bool             `json:"webhook"`
+}
+
+type OpeningHours struct {
+	Closes  string   `json:"closes"`
+	Days    []string `json:"days"`
+	Label   string   `json:"label"`
+	LabelHe string   `json:"labelHe,omitempty"`
+	Opens   string   `json:"opens"`
+}
+
+// OutboundUsage: Today's fotmob.com requests against the daily budget; a budget of 0 means unlimited
+type OutboundUsage struct {
+	BackgroundBudget int            `json:"backgroundBudget"`
+	Budget           int            `json:"budget"`
+	ByCaller         map[string]int `json:"byCaller"`
+	ByPriority       map[string]int `json:"byPriority"`
+	Day              string         `json:"day"`
+	Refused          int            `json:"refused"`
+	Remaining        int            `json:"remaining"`
+	ResetsAt         time.Time      `json:"resetsAt"`
+	Throttled        int            `json:"throttled"`
+	Used             int            `json:"used"`
+}
+
+// Override: An admin correction merged over provider data; fields left out keep the provider's value. Expires once upstream agrees
+type Override struct {
+	AwayTeam  string     `json:"awayTeam,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	CreatedBy string     `json:"createdBy"`
+	HomeTeam  string     `json:"homeTeam,omitempty"`
+	Kickoff   *time.Time `json:"kickoff,omitempty"`
+	Manual    bool       `json:"manual,omitempty"`
+	MatchID   int        `json:"matchId"`
+	Note      string     `json:"note,omitempty"`
+	Round     string     `json:"round,omitempty"`
+	Status    string     `json:"status,omitempty"`
+	TimeTBC   bool       `json:"timeTbc,omitempty"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	UpdatedBy string     `json:"updatedBy"`
+	Venue     string     `json:"venue,omitempty"`
+}
+
+// OverrideAudit: Changes to the overrides, newest first
+type OverrideAudit struct {
+	Audit []OverrideAuditEntry `json:"audit"`
+}
+
+type OverrideAuditEntry struct {
+	Action  string    `json:"action"`
+	Actor   string    `json:"actor"`
+	After   *Override `json:"after,omitempty"`
+	Before  *Override `json:"before,omitempty"`
+	MatchID int       `json:"matchId"`
+	Reason  string    `json:"reason,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+type OverrideList struct {
+	Overrides []Override `json:"overrides"`
+}
+
+// OverrideRequest: An override to create or replace. A manual fixture needs homeTeam, awayTeam and kickoff; any other needs matchId and at least one of kickoff, timeTbc, venue and status
+type OverrideRequest struct {
+	AwayTeam string     `json:"awayTeam,omitempty"`
+	HomeTeam string     `json:"homeTeam,omitempty"`
+	Kickoff  *time.Time `json:"kickoff,omitempty"`
+	Manual   bool       `json:"manual,omitempty"`
+	MatchID  int        `json:"matchId,omitempty"`
+	Note     string     `json:"note,omitempty"`
+	Round    string     `json:"round,omitempty"`
+	Status   string     `json:"status,omitempty"`
+	TimeTBC  bool       `json:"timeTbc,omitempty"`
+	Venue    string     `json:"venue,omitempty"`
+}
+
+type Pagination struct {
+	Page       int `json:"page"`
+	PerPage    int `json:"perPage"`
+	Total      int `json:"total"`
+	TotalPages int `json:"totalPages"`
+}
+
+type ParkingLot struct {
+	AccessibleSpaces int          `json:"accessibleSpaces,omitempty"`
+	Coordinates      *Coordinates `json:"coordinates,omitempty"`
+	Name             string       `json:"name"`
+	NameHe           string       `json:"nameHe,omitempty"`
+	Notes            string       `json:"notes,omitempty"`
+	NotesHe          string       `json:"notesHe,omitempty"`
+	Paid             bool         `json:"paid"`
+	Spaces           int          `json:"spaces,omitempty"`
+}
+
+type Photo struct {
+	Caption   string `json:"caption,omitempty"`
+	CaptionHe string `json:"captionHe,omitempty"`
+	Credit    string `json:"credit,omitempty"`
+	URL       string `json:"url"`
+}
+
+type PurgeResult struct {
+	Purged map[string]int `json:"purged"`
+}
+
+// RateLimited: Returned with 429 and a Retry-After header
+type RateLimited struct {
+	Message       string    `json:"message"`
+	NextAllowedAt time.Time `json:"nextAllowedAt"`
+	RetryAfter    int       `json:"retryAfter"`
+	Success       bool      `json:"success"`
+}
+
+type RefreshAttempt struct {
+	Duration     string    `json:"duration"`
+	Error        string    `json:"error,omitempty"`
+	Source       string    `json:"source"`
+	StartedAt    time.Time `json:"startedAt"`
+	Success      bool      `json:"success"`
+	TokenPreview string    `json:"tokenPreview,omitempty"`
+	Trigger      string    `json:"trigger"`
+}
+
+type RefreshHistory struct {
+	History []RefreshAttempt       `json:"history"`
+	Sources map[string]SourceStats `json:"sources"`
+}
+
+type RefreshTokenResponse struct {
+	Message      string    `json:"message"`
+	Source       string    `json:"source"`
+	Success      bool      `json:"success"`
+	Timestamp    time.Time `json:"timestamp"`
+	TokenPreview string    `json:"tokenPreview"`
+}
+
+// ScheduleChange: A fixture that appeared at Sammy Ofer or moved to, from or within it, with the clashes that created
+type ScheduleChange struct {
+	Conflicts       []Conflict `json:"conflicts,omitempty"`
+	DetectedAt      time.Time  `json:"detectedAt"`
+	Match           Match      `json:"match"`
+	PreviousKickoff *time.Time `json:"previousKickoff,omitempty"`
+	PreviousVenue   string     `json:"previousVenue,omitempty"`
+	Type            string     `json:"type"`
+}
+
+type SourceStats struct {
+	Failures    int        `json:"failures"`
+	LastFailure *time.Time `json:"lastFailure,omitempty"`
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+	Successes   int        `json:"successes"`
+}
+
+// StadiumInfo: Sammy Ofer Stadium details, localized
+type StadiumInfo struct {
+	Accessibility []Facility     `json:"accessibility"`
+	Address       string         `json:"address"`
+	Capacity      int            `json:"capacity"`
+	City          string         `json:"city"`
+	Clubs         []Team         `json:"clubs,omitempty"`
+	Coordinates   *Coordinates   `json:"coordinates,omitempty"`
+	Country       string         `json:"country"`
+	Description   string         `json:"description"`
+	Gates         []Gate         `json:"gates"`
+	ImageURL      string         `json:"imageUrl"`
+	Name          string         `json:"name"`
+	OpeningHours  []OpeningHours `json:"openingHours"`
+	Parking       []ParkingLot   `json:"parking"`
+	Photos        []Photo        `json:"photos"`
+	Slug          string         `json:"slug"`
+	Stands        []Stand        `json:"stands"`
+	Teams         []string       `json:"teams"`
+	UpdatedAt     time.Time      `json:"updatedAt"`
+	Version       int            `json:"version"`
+}
+
+type StadiumList struct {
+	Stadiums []StadiumProfile `json:"stadiums"`
+}
+
+// StadiumProfile: An editable stadium profile; the He fields are the Hebrew text, falling back to the English when empty
+type StadiumProfile struct {
+	Accessibility []Facility     `json:"accessibility"`
+	Address       string         `json:"address"`
+	AddressHe     string         `json:"addressHe,omitempty"`
+	Capacity      int            `json:"capacity"`
+	City          string         `json:"city"`
+	CityHe        string         `json:"cityHe,omitempty"`
+	Coordinates   *Coordinates   `json:"coordinates,omitempty"`
+	Country       string         `json:"country"`
+	CountryHe     string         `json:"countryHe,omitempty"`
+	Description   string         `json:"description"`
+	DescriptionHe string         `json:"descriptionHe,omitempty"`
+	Gates         []Gate         `json:"gates"`
+	ImageURL      string         `json:"imageUrl"`
+	Name          string         `json:"name"`
+	NameHe        string         `json:"nameHe,omitempty"`
+	OpeningHours  []OpeningHours `json:"openingHours"`
+	Parking       []ParkingLot   `json:"parking"`
+	Photos        []Photo        `json:"photos"`
+	Slug          string         `json:"slug"`
+	Stands        []Stand        `json:"stands"`
+	UpdatedAt     time.Time      `json:"updatedAt"`
+	UpdatedBy     string         `json:"updatedBy,omitempty"`
+	Version       int            `json:"version"`
+}
+
+// StadiumProfileRequest: A stadium profile to store; slug is taken from the path on PUT, and version, when given, must be the current one
+type StadiumProfileRequest struct {
+	Accessibility []Facility     `json:"accessibility,omitempty"`
+	Address       string         `json:"address,omitempty"`
+	AddressHe     string         `json:"addressHe,omitempty"`
+	Capacity      int            `json:"capacity,omitempty"`
+	City          string         `json:"city,omitempty"`
+	CityHe        string         `json:"cityHe,omitempty"`
+	Coordinates   *Coordinates   `json:"coordinates,omitempty"`
+	Country       string         `json:"country,omitempty"`
+	CountryHe     string         `json:"countryHe,omitempty"`
+	Description   string         `json:"description,omitempty"`
+	DescriptionHe string         `json:"descriptionHe,omitempty"`
+	Gates         []Gate         `json:"gates,omitempty"`
+	ImageURL      string         `json:"imageUrl,omitempty"`
+	Name          string         `json:"name"`
+	NameHe        string         `json:"nameHe,omitempty"`
+	OpeningHours  []OpeningHours `json:"openingHours,omitempty"`
+	Parking       []ParkingLot   `json:"parking,omitempty"`
+	Photos        []Photo        `json:"photos,omitempty"`
+	Slug          string         `json:"slug,omitempty"`
+	Stands        []Stand        `json:"stands,omitempty"`
+	UpdatedAt     *time.Time     `json:"updatedAt,omitempty"`
+	UpdatedBy     string         `json:"updatedBy,omitempty"`
+	Version       int            `json:"version,omitempty"`
+}
+
+type StadiumResponse struct {
+	Data StadiumInfo `json:"data"`
+	Meta Meta        `json:"meta"`
+}
+
+// StadiumVersion: One edit of a stadium with the profile as it was afterwards; a deletion has no profile
+type StadiumVersion struct {
+	Action  string          `json:"action"`
+	Actor   string          `json:"actor"`
+	Changed []string        `json:"changed,omitempty"`
+	Profile *StadiumProfile `json:"profile,omitempty"`
+	Slug    string          `json:"slug"`
+	Time    time.Time       `json:"time"`
+	Version int             `json:"version"`
+}
+
+// StadiumVersions: Versions newest first
+type StadiumVersions struct {
+	Versions []StadiumVersion `json:"versions"`
+}
+
+type Stand struct {
+	Capacity int      `json:"capacity,omitempty"`
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	NameHe   string   `json:"nameHe,omitempty"`
+	Sections []string `json:"sections"`
+}
+
+type StatGroup struct {
+	Key   string     `json:"key"`
+	Stats []TeamStat `json:"stats"`
+	Title string     `json:"title"`
+}
+
+type SubstitutionEvent struct {
+	AddedTime   int    `json:"addedTime,omitempty"`
+	IsHome      bool   `json:"isHome"`
+	Minute      int    `json:"minute"`
+	PlayerIn    string `json:"playerIn"`
+	PlayerInID  int    `json:"playerInId"`
+	PlayerOut   string `json:"playerOut"`
+	PlayerOutID int    `json:"playerOutId"`
+}
+
+// Team: A club from the team registry
+type Team struct {
+	Aliases     []string `json:"aliases,omitempty"`
+	HomeVenue   string   `json:"homeVenue,omitempty"`
+	ID          int      `json:"id"`
+	LogoURL     string   `json:"logoUrl,omitempty"`
+	Name        string   `json:"name"`
+	NameHe      string   `json:"nameHe"`
+	ShortName   string   `json:"shortName"`
+	ShortNameHe string   `json:"shortNameHe"`
+}
+
+type TeamLineup struct {
+	Coach     string         `json:"coach,omitempty"`
+	Formation string         `json:"formation,omitempty"`
+	Starters  []LineupPlayer `json:"starters"`
+	Subs      []LineupPlayer `json:"subs"`
+	TeamID    int            `json:"teamId"`
+	TeamName  string         `json:"teamName"`
+}
+
+type TeamStat struct {
+	Away  string `json:"away"`
+	Home  string `json:"home"`
+	Key   string `json:"key"`
+	Title string `json:"title"`
+}
+
+// TokenInfo: A decoded x-mas token
+type TokenInfo struct {
+	Claims    map[string]interface{} `json:"claims"`
+	ExpiresAt *time.Time             `json:"expiresAt,omitempty"`
+	Format    string                 `json:"format"`
+	Header    map[string]interface{} `json:"header,omitempty"`
+	IssuedAt  *time.Time             `json:"issuedAt,omitempty"`
+}
+
+type TokenRefresh struct {
+	RefreshedAt  time.Time `json:"refreshedAt"`
+	Source       string    `json:"source"`
+	TokenPreview string    `json:"tokenPreview"`
+}
+
+type TokenRefreshResponse struct {
+	Data TokenRefresh `json:"data"`
+}
+
+type TransitCall struct {
+	Stop string    `json:"stop"`
+	Time time.Time `json:"time"`
+}
+
+// TransitRoute: A public transport line from the GTFS feed
+type TransitRoute struct {
+	Agency    string `json:"agency"`
+	Color     string `json:"color,omitempty"`
+	ID        string `json:"id"`
+	LongName  string `json:"longName"`
+	Mode      string `json:"mode"`
+	ShortName string `json:"shortName"`
+}
+
+// TransitStop: A stop within walking distance of the stadium
+type TransitStop struct {
+	DistanceMeters int     `json:"distanceMeters"`
+	ID             string  `json:"id"`
+	Lat            float64 `json:"lat"`
+	Lng            float64 `json:"lng"`
+	Name           string  `json:"name"`
+	WalkMinutes    int     `json:"walkMinutes"`
+}
+
+// TransitTrip: A suggested trip, at the nearby stop closest to the stadium it calls at
+type TransitTrip struct {
+	From        TransitCall  `json:"from"`
+	Headsign    string       `json:"headsign"`
+	Route       TransitRoute `json:"route"`
+	StadiumTime time.Time    `json:"stadiumTime"`
+	Stop        TransitStop  `json:"stop"`
+	Time        time.Time    `json:"time"`
+	To          TransitCall  `json:"to"`
+}
+
+type UpstreamStats struct {
+	Errors       int            `json:"errors"`
+	ErrorsByKind map[string]int `json:"errorsByKind"`
+	LastError    string         `json:"lastError,omitempty"`
+	LastErrorAt  *time.Time     `json:"lastErrorAt,omitempty"`
+	Requests     int            `json:"requests"`
+}
+
+type VenueConflicts struct {
+	Conflicts     []Conflict `json:"conflicts"`
+	Matches       int        `json:"matches"`
+	Slug          string     `json:"slug"`
+	Venue         string     `json:"venue"`
+	WindowMinutes int        `json:"windowMinutes"`
+}
+
+type VenueConflictsResponse struct {
+	Data VenueConflicts `json:"data"`
+	Meta Meta           `json:"meta"`
+}
+
+// ForceTokenSource: Force refreshes to use one token source
+//
+// PUT /api/admin/token/source
+func (c *Client) ForceTokenSource(ctx context.Context, body *ForceSourceRequest) (*ForceSourceResponse, error) {
+	path := "/api/admin/token/source"
+	var out ForceSourceResponse
+	if err := c.do(ctx, "PUT", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAdminToken: Current token, its decoded claims and source
+//
+// GET /api/admin/token
+func (c *Client) GetAdminToken(ctx context.Context) (*AdminToken, error) {
+	path := "/api/admin/token"
+	var out AdminToken
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetHeadToHeadParams are the optional query parameters of GetHeadToHead
+type GetHeadToHeadParams struct {
+	// Response language; overrides Accept-Language
+	Lang string
+}
+
+// GetHeadToHead: Head-to-head history for an upcoming fixture
+//
+// GET /api/v1/matches/{id}/h2h
+func (c *Client) GetHeadToHead(ctx context.Context, id int, params *GetHeadToHeadParams) (*HeadToHeadResponse, error) {
+	path := "/api/v1/matches/" + strconv.Itoa(id) + "/h2h"
+	query := url.Values{}
+	if params != nil {
+		if params.Lang != "" {
+			query.Set("lang", params.Lang)
+		}
+	}
+	var out HeadToHeadResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetHealth: Report whether the server is in normal or degraded mode
+//
+// GET /api/v1/health
+func (c *Client) GetHealth(ctx context.Context) (*HealthResponse, error) {
+	path := "/api/v1/health"
+	var out HealthResponse
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMatchParams are the optional query parameters of GetMatch
+type GetMatchParams struct {
+	// Response language; overrides Accept-Language
+	Lang string
+}
+
+// GetMatch: Lineups, goals, cards, substitutions and statistics for a match
+//
+// GET /api/v1/matches/{id}
+func (c *Client) GetMatch(ctx context.Context, id int, params *GetMatchParams) (*MatchDetailsResponse, error) {
+	path := "/api/v1/matches/" + strconv.Itoa(id)
+	query := url.Values{}
+	if params != nil {
+		if params.Lang != "" {
+			query.Set("lang", params.Lang)
+		}
+	}
+	var out MatchDetailsResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMatchTransitParams are the optional query parameters of GetMatchTransit
+type GetMatchTransitParams struct {
+	// Response language; overrides Accept-Language
+	Lang string
+}
+
+// GetMatchTransit: Trains and buses to and from an upcoming match at Sammy Ofer
+//
+// GET /api/v1/matches/{id}/transit
+func (c *Client) GetMatchTransit(ctx context.Context, id int, params *GetMatchTransitParams) (*MatchTransitResponse, error) {
+	path := "/api/v1/matches/" + strconv.Itoa(id) + "/transit"
+	query := url.Values{}
+	if params != nil {
+		if params.Lang != "" {
+			query.Set("lang", params.Lang)
+		}
+	}
+	var out MatchTransitResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMatchTransitPlainParams are the optional query parameters of GetMatchTransitPlain
+type GetMatchTransitPlainParams struct {
+	// Response language; overrides Accept-Language
+	Lang string
+}
+
+// GetMatchTransitPlain: Trains and buses to and from an upcoming match at Sammy Ofer
+//
+// GET /api/matches/{id}/transit
+func (c *Client) GetMatchTransitPlain(ctx context.Context, id int, params *GetMatchTransitPlainParams) (*MatchTransit, error) {
+	path := "/api/matches/" + strconv.Itoa(id) + "/transit"
+	query := url.Values{}
+	if params != nil {
+		if params.Lang != "" {
+			query.Set("lang", params.Lang)
+		}
+	}
+	var out MatchTransit
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOpenAPISpec: This document
+//
+// GET /api/openapi.json
+func (c *Client) GetOpenAPISpec(ctx context.Context) (map[string]interface{}, error) {
+	path := "/api/openapi.json"
+	var out map[string]interface{}
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetOutboundBudget: Today's fotmob.com requests against the outbound budget
+//
+// GET /api/admin/budget
+func (c *Client) GetOutboundBudget(ctx context.Context) (*OutboundUsage, error) {
+	path := "/api/admin/budget"
+	var out OutboundUsage
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOverride: The override for a match
+//
+// GET /api/admin/overrides/{id}
+func (c *Client) GetOverride(ctx context.Context, id int) (*Override, error) {
+	path := "/api/admin/overrides/" + strconv.Itoa(id)
+	var out Override
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetOverrideAuditParams are the optional query parameters of GetOverrideAudit
+type GetOverrideAuditParams struct {
+	// Only changes to this match
+	MatchID int
+}
+
+// GetOverrideAudit: Audit trail of override changes
+//
+// GET /api/admin/overrides/audit
+func (c *Client) GetOverrideAudit(ctx context.Context, params *GetOverrideAuditParams) (*OverrideAudit, error) {
+	path := "/api/admin/overrides/audit"
+	query := url.Values{}
+	if params != nil {
+		if params.MatchID != 0 {
+			query.Set("matchId", strconv.Itoa(params.MatchID))
+		}
+	}
+	var out OverrideAudit
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetRefreshHistory: Recent token refresh attempts and per-source outcomes
+//
+// GET /api/admin/refreshes
+func (c *Client) GetRefreshHistory(ctx context.Context) (*RefreshHistory, error) {
+	path := "/api/admin/refreshes"
+	var out RefreshHistory
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetStadiumParams are the optional query parameters of GetStadium
+type GetStadiumParams struct {
+	// Response language; overrides Accept-Language
+	Lang string
+}
+
+// GetStadium: Sammy Ofer Stadium information
+//
+// GET /api/v1/stadium
+func (c *Client) GetStadium(ctx context.Context, params *GetStadiumParams) (*StadiumResponse, error) {
+	path := "/api/v1/stadium"
+	query := url.Values{}
+	if params != nil {
+		if params.Lang != "" {
+			query.Set("lang", params.Lang)
+		}
+	}
+	var out StadiumResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetStadiumBusyWindowsParams are the optional query parameters of GetStadiumBusyWindows
+type GetStadiumBusyWindowsParams struct {
+	// Response language; overrides Accept-Language
+	Lang string
+}
+
+// GetStadiumBusyWindows: When the area around Sammy Ofer Stadium is busy with match crowds
+//
+// GET /api/v1/stadium/busy
+func (c *Client) GetStadiumBusyWindows(ctx context.Context, params *GetStadiumBusyWindowsParams) (*BusyCalendarResponse, error) {
+	path := "/api/v1/stadium/busy"
+	query := url.Values{}
+	if params != nil {
+		if params.Lang != "" {
+			query.Set("lang", params.Lang)
+		}
+	}
+	var out BusyCalendarResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetStadiumProfile: A stadium profile with its Hebrew text
+//
+// GET /api/admin/stadiums/{slug}
+func (c *Client) GetStadiumProfile(ctx context.Context, slug string) (*StadiumProfile, error) {
+	path := "/api/admin/stadiums/" + url.PathEscape(slug)
+	var out StadiumProfile
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetStadiumVersion: One edit of a stadium
+//
+// GET /api/admin/stadiums/{slug}/versions/{version}
+func (c *Client) GetStadiumVersion(ctx context.Context, slug string, version int) (*StadiumVersion, error) {
+	path := "/api/admin/stadiums/" + url.PathEscape(slug) + "/versions/" + strconv.Itoa(version)
+	var out StadiumVersion
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetUpstreamStats: Fotmob request and error counts since startup
+//
+// GET /api/admin/upstream
+func (c *Client) GetUpstreamStats(ctx context.Context) (*UpstreamStats, error) {
+	path := "/api/admin/upstream"
+	var out UpstreamStats
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetVenueConflictsParams are the optional query parameters of GetVenueConflicts
+type GetVenueConflictsParams struct {
+	// How close fixtures may be before they conflict, as a duration such as 48h, at most 168h (default CONFLICT_WINDOW)
+	Window string
+	// Response language; overrides Accept-Language
+	Lang string
+}
+
+// GetVenueConflicts: Fixtures at a venue that clash or fall on the same or consecutive days
+//
+// GET /api/venues/{slug}/conflicts
+func (c *Client) GetVenueConflicts(ctx context.Context, slug string, params *GetVenueConflictsParams) (*VenueConflicts, error) {
+	path := "/api/venues/" + url.PathEscape(slug) + "/conflicts"
+	query := url.Values{}
+	if params != nil {
+		if params.Window != "" {
+			query.Set("window", params.Window)
+		}
+		if params.Lang != "" {
+			query.Set("lang", params.Lang)
+		}
+	}
+	var out VenueConflicts
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListCaches: In-memory cache entries and their ages
+//
+// GET /api/admin/caches
+func (c *Client) ListCaches(ctx context.Context) (CacheEntries, error) {
+	path := "/api/admin/caches"
+	var out CacheEntries
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListCompetitions: Competitions the configured fixture providers carry
+//
+// GET /api/v1/competitions
+func (c *Client) ListCompetitions(ctx context.Context) (*CompetitionListResponse, error) {
+	path := "/api/v1/competitions"
+	var out CompetitionListResponse
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListMatchesParams are the optional query parameters of ListMatches
+type ListMatchesParams struct {
+	// Response language; overrides Accept-Language
+	Lang string
+	// Page number, from 1
+	Page int
+	// Matches per page, 1 to 100 (default 20)
+	PerPage int
+}
+
+// ListMatches: Upcoming matches at Sammy Ofer Stadium, soonest first
+//
+// GET /api/v1/matches
+func (c *Client) ListMatches(ctx context.Context, params *ListMatchesParams) (*MatchListResponse, error) {
+	path := "/api/v1/matches"
+	query := url.Values{}
+	if params != nil {
+		if params.Lang != "" {
+			query.Set("lang", params.Lang)
+		}
+		if params.Page != 0 {
+			query.Set("page", strconv.Itoa(params.Page))
+		}
+		if params.PerPage != 0 {
+			query.Set("perPage", strconv.Itoa(params.PerPage))
+		}
+	}
+	var out MatchListResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListNotifications: Recent schedule changes at Sammy Ofer and the conflicts they created
+//
+// GET /api/admin/notifications
+func (c *Client) ListNotifications(ctx context.Context) (*NotificationList, error) {
+	path := "/api/admin/notifications"
+	var out NotificationList
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListOverrides: Fixture overrides in effect
+//
+// GET /api/admin/overrides
+func (c *Client) ListOverrides(ctx context.Context) (*OverrideList, error) {
+	path := "/api/admin/overrides"
+	var out OverrideList
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListStadiumVersions: Every edit of a stadium
+//
+// GET /api/admin/stadiums/{slug}/versions
+func (c *Client) ListStadiumVersions(ctx context.Context, slug string) (*StadiumVersions, error) {
+	path := "/api/admin/stadiums/" + url.PathEscape(slug) + "/versions"
+	var out StadiumVersions
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListStadiums: Stadium profiles
+//
+// GET /api/admin/stadiums
+func (c *Client) ListStadiums(ctx context.Context) (*StadiumList, error) {
+	path := "/api/admin/stadiums"
+	var out StadiumList
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListVenueConflictsParams are the optional query parameters of ListVenueConflicts
+type ListVenueConflictsParams struct {
+	// How close fixtures may be before they conflict, as a duration such as 48h, at most 168h (default CONFLICT_WINDOW)
+	Window string
+	// Response language; overrides Accept-Language
+	Lang string
+}
+
+// ListVenueConflicts: Fixtures at a venue that clash or fall on the same or consecutive days
+//
+// GET /api/v1/venues/{slug}/conflicts
+func (c *Client) ListVenueConflicts(ctx context.Context, slug string, params *ListVenueConflictsParams) (*VenueConflictsResponse, error) {
+	path := "/api/v1/venues/" + url.PathEscape(slug) + "/conflicts"
+	query := url.Values{}
+	if params != nil {
+		if params.Window != "" {
+			query.Set("window", params.Window)
+		}
+		if params.Lang != "" {
+			query.Set("lang", params.Lang)
+		}
+	}
+	var out VenueConflictsResponse
+	if err := c.do(ctx, "GET", path, query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PatchStadium: Change some fields of a stadium profile
+//
+// PATCH /api/admin/stadiums/{slug}
+func (c *Client) PatchStadium(ctx context.Context, slug string, body map[string]interface{}) (*StadiumProfile, error) {
+	path := "/api/admin/stadiums/" + url.PathEscape(slug)
+	var out StadiumProfile
+	if err := c.do(ctx, "PATCH", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ProbeHealth: Report whether the server is in normal or degraded mode, for load balancers
+//
+// GET /api/health
+func (c *Client) ProbeHealth(ctx context.Context) (*Health, error) {
+	path := "/api/health"
+	var out Health
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PurgeCache: Purge one in-memory cache
+//
+// DELETE /api/admin/caches/{name}
+func (c *Client) PurgeCache(ctx context.Context, name string) (*PurgeResult, error) {
+	path := "/api/admin/caches/" + url.PathEscape(name)
+	var out PurgeResult
+	if err := c.do(ctx, "DELETE", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PurgeCaches: Purge every in-memory cache
+//
+// DELETE /api/admin/caches
+func (c *Client) PurgeCaches(ctx context.Context) (*PurgeResult, error) {
+	path := "/api/admin/caches"
+	var out PurgeResult
+	if err := c.do(ctx, "DELETE", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RefreshToken: Scrape a new Fotmob token
+//
+// POST /api/v1/token/refresh
+func (c *Client) RefreshToken(ctx context.Context) (*TokenRefreshResponse, error) {
+	path := "/api/v1/token/refresh"
+	var out TokenRefreshResponse
+	if err := c.do(ctx, "POST", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RestoreStadiumVersion: Make an earlier version current again, as a new version
+//
+// POST /api/admin/stadiums/{slug}/versions/{version}/restore
+func (c *Client) RestoreStadiumVersion(ctx context.Context, slug string, version int) (*StadiumProfile, error) {
+	path := "/api/admin/stadiums/" + url.PathEscape(slug) + "/versions/" + strconv.Itoa(version) + "/restore"
+	var out StadiumProfile
+	if err := c.do(ctx, "POST", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateOverride: Replace the override for a match
+//
+// PUT /api/admin/overrides/{id}
+func (c *Client) UpdateOverride(ctx context.Context, id int, body *OverrideRequest) (*Override, error) {
+	path := "/api/admin/overrides/" + strconv.Itoa(id)
+	var out Override
+	if err := c.do(ctx, "PUT", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateStadium: Replace a stadium profile
+//
+// PUT /api/admin/stadiums/{slug}
+func (c *Client) UpdateStadium(ctx context.Context, slug string, body *StadiumProfileRequest) (*StadiumProfile, error) {
+	path := "/api/admin/stadiums/" + url.PathEscape(slug)
+	var out StadiumProfile
+	if err := c.do(ctx, "PUT", path, nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
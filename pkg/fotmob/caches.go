@@ -0,0 +1,13 @@
+package fotmob
+
+import "github.com/MichaelBabushkin/sammy_po/pkg/cache"
+
+// Caches returns the package's in-memory caches by name, for inspection
+// and purging from the admin endpoints
+func Caches() map[string]*cache.Cache {
+	return map[string]*cache.Cache{
+		"matchDetails": matchDetailsCache,
+		"headToHead":   h2hCache,
+		"venues":       venueCache,
+	}
+}
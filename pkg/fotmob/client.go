@@ -0,0 +1,217 @@
+package fotmob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/api"
+)
+
+// Timeouts bound each kind of Fotmob call. They apply on top of the
+// caller's context, whichever ends first.
+type Timeouts struct {
+	League       time.Duration // current league fixtures
+	Season       time.Duration // a past season, for head-to-head
+	MatchDetails time.Duration
+}
+
+// DefaultTimeouts are used by NewFotmobClient
+var DefaultTimeouts = Timeouts{
+	League:       15 * time.Second,
+	Season:       20 * time.Second,
+	MatchDetails: 10 * time.Second,
+}
+
+type FotmobClient struct {
+	client     *http.Client
+	timeouts   Timeouts
+	resilience Resilience
+	breaker    *breaker
+}
+
+func NewFotmobClient() *FotmobClient {
+	return NewFotmobClientWithTimeouts(DefaultTimeouts)
+}
+
+// NewFotmobClientWithTimeouts creates a client with per-call deadlines; zero
+// fields fall back to DefaultTimeouts
+func NewFotmobClientWithTimeouts(timeouts Timeouts) *FotmobClient {
+	return NewFotmobClientWithResilience(timeouts, DefaultResilience)
+}
+
+// NewFotmobClientWithResilience also sets the retry and circuit breaker
+// policies; zero fields fall back to DefaultResilience
+func NewFotmobClientWithResilience(timeouts Timeouts, resilience Resilience) *FotmobClient {
+	if timeouts.League <= 0 {
+		timeouts.League = DefaultTimeouts.League
+	}
+	if timeouts.Season <= 0 {
+		timeouts.Season = DefaultTimeouts.Season
+	}
+	if timeouts.MatchDetails <= 0 {
+		timeouts.MatchDetails = DefaultTimeouts.MatchDetails
+	}
+	if resilience.Retry.MaxAttempts <= 0 {
+		resilience.Retry.MaxAttempts = DefaultResilience.Retry.MaxAttempts
+	}
+	if resilience.Retry.BaseDelay <= 0 {
+		resilience.Retry.BaseDelay = DefaultResilience.Retry.BaseDelay
+	}
+	if resilience.Retry.MaxDelay <= 0 {
+		resilience.Retry.MaxDelay = DefaultResilience.Retry.MaxDelay
+	}
+	if resilience.Breaker.Threshold <= 0 {
+		resilience.Breaker.Threshold = DefaultResilience.Breaker.Threshold
+	}
+	if resilience.Breaker.Cooldown <= 0 {
+		resilience.Breaker.Cooldown = DefaultResilience.Breaker.Cooldown
+	}
+	return &FotmobClient{
+		// Backstop for callers that pass a context without a deadline
+		client:     &http.Client{Timeout: time.Minute},
+		timeouts:   timeouts,
+		resilience: resilience,
+		breaker:    newBreaker(resilience.Breaker),
+	}
+}
+
+// SetTransport replaces how requests reach Fotmob, so tests can run
+// without the network
+func (c *FotmobClient) SetTransport(transport http.RoundTripper) {
+	c.client.Transport = transport
+}
+
+// doRequest makes a single attempt at a Fotmob GET
+func (c *FotmobClient) doRequest(ctx context.Context, url string, timeout time.Duration) ([]byte, error) {
+	log.Printf("Making Fotmob request to: %s", url)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the headers from our API package
+	headers, err := api.GetFotmobHeaders()
+	if err != nil {
+		recordUpstream(url, "no_token", err)
+		return nil, err
+	}
+	
+	// Log the token info including timestamp
+	log.Printf("Using x-mas token: %s... (scraped at: %s)", 
+		truncateToken(headers.XMasToken), 
+		time.Unix(headers.ScrapedAt, 0).Format(time.RFC3339))
+	
+	// Add the headers to the request
+	req.Header.Add("x-mas", headers.XMasToken)
+	req.Header.Add("User-Agent", headers.UserAgent)
+	if headers.Accept != "" {
+		req.Header.Add("Accept", headers.Accept)
+	}
+	
+	// Add any other important headers we might have discovered
+	for k, v := range headers.AllHeaders {
+		if k != "x-mas" && k != "User-Agent" && k != "Accept" {
+			if !strings.HasPrefix(k, ":") && k != "Connection" && k != "Host" {
+				req.Header.Add(k, v)
+			}
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		recordUpstream(url, requestErrorKind(err), err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	switch {
+	case err != nil && ctx.Err() != nil:
+		recordUpstream(url, requestErrorKind(ctx.Err()), err)
+	case err != nil:
+		recordUpstream(url, "read", err)
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		err = fmt.Errorf("%w: Fotmob answered %s", ErrTokenRejected, resp.Status)
+		recordUpstream(url, fmt.Sprintf("http_%d", resp.StatusCode), err)
+		return nil, err
+	case resp.StatusCode >= 400:
+		err = &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+		recordUpstream(url, fmt.Sprintf("http_%d", resp.StatusCode), err)
+		return nil, err
+	default:
+		recordUpstream(url, "", nil)
+	}
+	return body, err
+}
+
+// requestErrorKind labels a failed request for the upstream stats, telling
+// deadlines and cancelled callers apart from network failures
+func requestErrorKind(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return "network"
+	}
+}
+
+// Helper function to truncate token for logging
+func truncateToken(token string) string {
+	if len(token) > 30 {
+		return token[:30] + "..."
+	}
+	return token
+}
+
+func (c *FotmobClient) FetchIsraeliLeagueData(ctx context.Context) (map[string]interface{}, error) {
+	url := "https://www.fotmob.com/api/leagues?id=127&ccode3=ISR"
+	body, err := c.makeRequest(ctx, url, c.timeouts.League)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	storeLeagueData(body)
+	return result, nil
+}
+
+func (c *FotmobClient) FetchIsraeliLeagueMatches(ctx context.Context) (interface{}, error) {
+	fullData, err := c.FetchIsraeliLeagueData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	matches, ok := fullData["matches"]
+	if !ok {
+		return nil, fmt.Errorf("matches field not found in API response")
+	}
+	
+	matchesMap, ok := matches.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("matches is not a proper object")
+	}
+	
+	allMatches, ok := matchesMap["allMatches"]
+	if !ok {
+		return nil, fmt.Errorf("allMatches field not found in matches object")
+	}
+	
+	return allMatches, nil
+}
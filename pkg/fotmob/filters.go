@@ -0,0 +1,132 @@
+package fotmob
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+)
+
+// FilterMatches filters matches based on provided criteria
+func FilterMatches(matches []interface{}, teamName string, isHome bool) []interface{} {
+	filtered := []interface{}{}
+	target, hasTarget := teams.Default.LookupName(teamName)
+	
+	for _, match := range matches {
+		matchMap, ok := match.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		
+		var teamToCheck map[string]interface{}
+		var teamKey string
+		
+		if isHome {
+			teamKey = "home"
+		} else {
+			teamKey = "away"
+		}
+		
+		teamData, ok := matchMap[teamKey]
+		if !ok {
+			continue
+		}
+		
+		teamToCheck, ok = teamData.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		
+		name, ok := teamToCheck["name"].(string)
+		if !ok {
+			continue
+		}
+		
+		// Compare registry identities rather than substrings so that
+		// "Maccabi Haifa U19" doesn't count as "Maccabi Haifa"
+		team, resolved := teams.Default.Resolve(teamID(teamToCheck), name)
+		if hasTarget && resolved && team.Name == target.Name {
+			filtered = append(filtered, match)
+		} else if !hasTarget && teams.Normalize(name) == teams.Normalize(teamName) {
+			filtered = append(filtered, match)
+		}
+	}
+	
+	return filtered
+}
+
+// FilterSammyOferMatches filters matches played at Sammy Ofer Stadium, as
+// decided by DetectVenue: relocated Haifa home games are left out and other
+// teams' games moved there are kept
+func FilterSammyOferMatches(matches []interface{}) []interface{} {
+	stadiumMatches := []interface{}{}
+	now := time.Now().UTC()
+	
+	// First: collect all matches at the stadium
+	for _, match := range matches {
+		matchMap, ok := match.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		
+		venue, _ := DetectVenue(matchMap)
+		if teams.SameVenue(venue, teams.SammyOferStadium) {
+			stadiumMatches = append(stadiumMatches, match)
+		}
+	}
+	
+	// Second: separate upcoming and past matches
+	upcomingMatches := []interface{}{}
+	pastMatches := []interface{}{}
+	
+	for _, match := range stadiumMatches {
+		matchMap := match.(map[string]interface{})
+		
+		matchTime, ok := matchKickoff(matchMap)
+		if !ok {
+			continue
+		}
+		
+		if matchTime.After(now) {
+			upcomingMatches = append(upcomingMatches, match)
+		} else {
+			pastMatches = append(pastMatches, match)
+		}
+	}
+	
+	// Return upcoming matches first, then past matches
+	return append(upcomingMatches, pastMatches...)
+}
+
+// Helper function to get team name from match object
+func getTeamName(matchMap map[string]interface{}, side string) string {
+	if teamData, ok := matchMap[side]; ok {
+		if team, ok := teamData.(map[string]interface{}); ok {
+			if name, ok := team["name"].(string); ok {
+				return name
+			}
+		}
+	}
+	return side
+}
+
+// Helper function to get the Fotmob team ID from a team object
+func teamID(team map[string]interface{}) int {
+	id, _ := strconv.Atoi(idString(team["id"]))
+	return id
+}
+
+// AnnotateClubs attaches the registry entry for each side of a match under
+// a "club" key, so clients get canonical names, Hebrew names and logos
+func AnnotateClubs(matchMap map[string]interface{}) {
+	for _, side := range []string{"home", "away"} {
+		team, ok := matchMap[side].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := team["name"].(string)
+		if club, ok := teams.Default.Resolve(teamID(team), name); ok {
+			team["club"] = club
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package fotmob
+
+import (
+	"strconv"
+	"time"
+)
+
+// KickoffMaxAge says how long clients may cache data about a match that
+// kicks off at kickoff. The closer the kickoff, the sooner lineups, times
+// and statuses change.
+func KickoffMaxAge(kickoff time.Time) time.Duration {
+	until := time.Until(kickoff)
+	switch {
+	case until <= 0:
+		return liveMatchTTL
+	case until < 3*time.Hour:
+		return time.Minute
+	case until < 24*time.Hour:
+		return upcomingMatchTTL
+	default:
+		return time.Hour
+	}
+}
+
+// NextKickoff returns the earliest kickoff among raw matches that hasn't
+// happened yet
+func NextKickoff(matches []interface{}) (time.Time, bool) {
+	var next time.Time
+	now := time.Now()
+	for _, match := range matches {
+		matchMap, ok := match.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		kickoff, ok := matchKickoff(matchMap)
+		if !ok || kickoff.Before(now) {
+			continue
+		}
+		if next.IsZero() || kickoff.Before(next) {
+			next = kickoff
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// MatchDetailsMaxAge combines the status-based cache lifetime with how soon
+// an upcoming match kicks off
+func MatchDetailsMaxAge(details *MatchDetails) time.Duration {
+	ttl := MatchDetailsTTL(details)
+	if details.Status.Started || details.Status.Finished || details.Status.Cancelled {
+		return ttl
+	}
+	if kickoff, err := ParseKickoff(details.UTCTime); err == nil {
+		if maxAge := KickoffMaxAge(kickoff); maxAge < ttl {
+			return maxAge
+		}
+	}
+	return ttl
+}
+
+// MatchDetailsFetchedAt returns when details for matchID were fetched from
+// Fotmob, or the zero time if they aren't cached
+func MatchDetailsFetchedAt(matchID int) time.Time {
+	if entry, ok := matchDetailsCache.GetEntry(strconv.Itoa(matchID)); ok {
+		return entry.FetchedAt
+	}
+	return time.Time{}
+}
+
+// HeadToHeadFetchedAt returns when the summary for matchID was built, or the
+// zero time if it isn't cached
+func HeadToHeadFetchedAt(matchID int) time.Time {
+	if entry, ok := h2hCache.GetEntry(strconv.Itoa(matchID)); ok {
+		return entry.FetchedAt
+	}
+	return time.Time{}
+}
@@ -0,0 +1,411 @@
+package fotmob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/cache"
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+)
+
+const (
+	// How many previous seasons to scan for meetings between two clubs
+	h2hSeasonLimit = 5
+	// How many recent meetings to include in the response
+	h2hRecentLimit = 5
+)
+
+// HeadToHeadTTL is how long a computed head-to-head summary is reused
+const HeadToHeadTTL = time.Hour
+
+// partialHeadToHeadTTL is how long a summary missing seasons that couldn't
+// be loaded is reused, so it is rebuilt soon after upstream recovers
+const partialHeadToHeadTTL = time.Minute
+
+// HeadToHeadMaxAge is how long h2h may be reused
+func HeadToHeadMaxAge(h2h *HeadToHead) time.Duration {
+	if len(h2h.SkippedSeasons) > 0 {
+		return partialHeadToHeadTTL
+	}
+	return HeadToHeadTTL
+}
+
+var h2hCache = cache.New()
+
+// HeadToHeadCached reports whether a fresh summary for matchID is cached
+func HeadToHeadCached(matchID int) bool {
+	_, ok := h2hCache.Get(strconv.Itoa(matchID))
+	return ok
+}
+
+// ErrMatchNotFound is returned when a match ID isn't part of the current season
+var ErrMatchNotFound = errors.New("match not found in current season")
+
+// HeadToHead summarises previous meetings between the two clubs of a
+// fixture. SkippedSeasons are seasons that couldn't be loaded, so their
+// meetings are missing from the counts. AtHomeVenue and AtAwayVenue count the meetings played at each
+// club's ground, going by each meeting's venue: a shared ground counts for
+// both, and a meeting elsewhere only in Totals.
+type HeadToHead struct {
+	MatchID        int             `json:"matchId"`
+	HomeTeam       H2HTeam         `json:"homeTeam"`
+	AwayTeam       H2HTeam         `json:"awayTeam"`
+	Seasons        []string        `json:"seasons"`
+	SkippedSeasons []string        `json:"skippedSeasons,omitempty"`
+	Totals         H2HRecord       `json:"totals"`
+	AtHomeVenue    H2HRecord       `json:"atHomeVenue"`
+	AtAwayVenue    H2HRecord       `json:"atAwayVenue"`
+	RecentMeetings []H2HMeeting    `json:"recentMeetings"`
+	Localized      *LocalizedMatch `json:"localized,omitempty"`
+}
+
+// H2HTeam identifies one of the two clubs
+type H2HTeam struct {
+	ID   string      `json:"id"`
+	Name string      `json:"name"`
+	Club *teams.Team `json:"club,omitempty"`
+}
+
+// H2HRecord is a win/draw/loss record seen from the fixture's home and away teams
+type H2HRecord struct {
+	Played        int `json:"played"`
+	HomeTeamWins  int `json:"homeTeamWins"`
+	AwayTeamWins  int `json:"awayTeamWins"`
+	Draws         int `json:"draws"`
+	HomeTeamGoals int `json:"homeTeamGoals"`
+	AwayTeamGoals int `json:"awayTeamGoals"`
+}
+
+// H2HMeeting is a single finished match between the two clubs
+type H2HMeeting struct {
+	MatchID    string `json:"matchId"`
+	Season     string `json:"season"`
+	UTCTime    string `json:"utcTime"`
+	HomeTeamID string `json:"homeTeamId"`
+	HomeTeam   string `json:"homeTeam"`
+	AwayTeamID string `json:"awayTeamId"`
+	AwayTeam   string `json:"awayTeam"`
+	HomeScore  int    `json:"homeScore"`
+	AwayScore  int    `json:"awayScore"`
+	Date       string `json:"date,omitempty"`
+	// Venue is where the meeting was played; VenueSource says how that was
+	// decided, see DetectVenue
+	Venue       string `json:"venue,omitempty"`
+	VenueSource string `json:"venueSource"`
+}
+
+// FetchIsraeliLeagueSeasonData fetches the league data for a specific season,
+// e.g. "2023/2024". An empty season returns the current one.
+func (c *FotmobClient) FetchIsraeliLeagueSeasonData(ctx context.Context, season string) (map[string]interface{}, error) {
+	if season == "" {
+		return c.FetchIsraeliLeagueData(ctx)
+	}
+
+	escapedSeason := url.QueryEscape(season)
+	url := "https://www.fotmob.com/api/leagues?id=127&ccode3=ISR&season=" + escapedSeason
+	body, err := c.makeRequest(ctx, url, c.timeouts.Season)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// extractAllMatches pulls matches.allMatches out of a league response
+func extractAllMatches(fullData map[string]interface{}) ([]interface{}, error) {
+	matches, ok := fullData["matches"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("matches field not found in API response")
+	}
+
+	allMatches, ok := matches["allMatches"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("allMatches field not found in matches object")
+	}
+
+	return allMatches, nil
+}
+
+// GetHeadToHead builds the head-to-head summary for an upcoming fixture, a
+// raw league match as the data layer serves it. Previous seasons are read
+// from disk when stored, otherwise fetched from Fotmob and stored for next
+// time since they no longer change.
+func GetHeadToHead(ctx context.Context, client *FotmobClient, fixture map[string]interface{}) (*HeadToHead, error) {
+	key := idString(fixture["id"])
+	matchID, _ := strconv.Atoi(key)
+	if cached, ok := h2hCache.Get(key); ok {
+		return cached.(*HeadToHead), nil
+	}
+
+	current, err := client.FetchIsraeliLeagueData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	currentMatches, err := extractAllMatches(current)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &HeadToHead{
+		MatchID:        matchID,
+		HomeTeam:       h2hTeamFromMatch(fixture, "home"),
+		AwayTeam:       h2hTeamFromMatch(fixture, "away"),
+		Seasons:        []string{},
+		RecentMeetings: []H2HMeeting{},
+	}
+
+	seasons := availableSeasons(current)
+	meetings := []H2HMeeting{}
+	seen := map[string]bool{}
+
+	collect := func(season string, matches []interface{}) {
+		for _, match := range matches {
+			matchMap, ok := match.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			meeting, ok := meetingFromMatch(matchMap, season, result.HomeTeam.ID, result.AwayTeam.ID)
+			if !ok || seen[meeting.MatchID] {
+				continue
+			}
+			seen[meeting.MatchID] = true
+			meetings = append(meetings, meeting)
+		}
+	}
+
+	currentSeason := ""
+	if len(seasons) > 0 {
+		currentSeason = seasons[0]
+		result.Seasons = append(result.Seasons, currentSeason)
+	}
+	collect(currentSeason, currentMatches)
+
+	for i := 1; i < len(seasons) && i <= h2hSeasonLimit; i++ {
+		season := seasons[i]
+		matches, err := loadSeasonMatches(ctx, client, season)
+		if err != nil {
+			if ctx.Err() != nil {
+				// The caller is gone or out of time; a partial summary
+				// must not be cached
+				return nil, ctx.Err()
+			}
+			log.Printf("Skipping season %s for head-to-head: %v", season, err)
+			result.SkippedSeasons = append(result.SkippedSeasons, season)
+			continue
+		}
+		result.Seasons = append(result.Seasons, season)
+		collect(season, matches)
+	}
+
+	// Most recent meetings first
+	sort.Slice(meetings, func(i, j int) bool {
+		return meetings[i].UTCTime > meetings[j].UTCTime
+	})
+
+	homeGround, awayGround := h2hGround(result.HomeTeam), h2hGround(result.AwayTeam)
+	for _, meeting := range meetings {
+		homeGoals, awayGoals := meeting.HomeScore, meeting.AwayScore
+		if meeting.HomeTeamID != result.HomeTeam.ID {
+			homeGoals, awayGoals = awayGoals, homeGoals
+		}
+
+		result.Totals.add(homeGoals, awayGoals)
+		if homeGround != "" && teams.SameVenue(meeting.Venue, homeGround) {
+			result.AtHomeVenue.add(homeGoals, awayGoals)
+		}
+		if awayGround != "" && teams.SameVenue(meeting.Venue, awayGround) {
+			result.AtAwayVenue.add(homeGoals, awayGoals)
+		}
+	}
+
+	if len(meetings) > h2hRecentLimit {
+		result.RecentMeetings = meetings[:h2hRecentLimit]
+	} else {
+		result.RecentMeetings = meetings
+	}
+
+	h2hCache.Set(key, result, HeadToHeadMaxAge(result))
+	return result, nil
+}
+
+// add records one meeting, with goals given from the fixture's home team's
+// point of view
+func (r *H2HRecord) add(homeTeamGoals, awayTeamGoals int) {
+	r.Played++
+	r.HomeTeamGoals += homeTeamGoals
+	r.AwayTeamGoals += awayTeamGoals
+
+	switch {
+	case homeTeamGoals > awayTeamGoals:
+		r.HomeTeamWins++
+	case homeTeamGoals < awayTeamGoals:
+		r.AwayTeamWins++
+	default:
+		r.Draws++
+	}
+}
+
+// availableSeasons returns the league's seasons, newest first
+func availableSeasons(leagueData map[string]interface{}) []string {
+	seasons := []string{}
+	raw, ok := leagueData["allAvailableSeasons"].([]interface{})
+	if !ok {
+		return seasons
+	}
+	for _, s := range raw {
+		if season, ok := s.(string); ok {
+			seasons = append(seasons, season)
+		}
+	}
+	return seasons
+}
+
+// loadSeasonMatches returns a finished season's matches from disk, fetching
+// and storing them when not available yet
+func loadSeasonMatches(ctx context.Context, client *FotmobClient, season string) ([]interface{}, error) {
+	seasonFile := seasonFilePath(season)
+
+	if data, err := ioutil.ReadFile(seasonFile); err == nil {
+		var matches []interface{}
+		if err := json.Unmarshal(data, &matches); err == nil {
+			return matches, nil
+		}
+		log.Printf("Stored season file %s is corrupt, fetching again", seasonFile)
+	}
+
+	leagueData, err := client.FetchIsraeliLeagueSeasonData(ctx, season)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := extractAllMatches(leagueData)
+	if err != nil {
+		return nil, err
+	}
+
+	os.MkdirAll(filepath.Dir(seasonFile), 0755)
+	data, _ := json.Marshal(matches)
+	if err := ioutil.WriteFile(seasonFile, data, 0644); err != nil {
+		log.Printf("Warning: Failed to store season file %s: %v", seasonFile, err)
+	}
+
+	return matches, nil
+}
+
+func seasonFilePath(season string) string {
+	name := strings.ReplaceAll(season, "/", "-")
+	return filepath.Join("responses", "seasons", "127_"+name+".json")
+}
+
+func h2hTeamFromMatch(matchMap map[string]interface{}, side string) H2HTeam {
+	team := H2HTeam{Name: getTeamName(matchMap, side)}
+	if teamMap, ok := matchMap[side].(map[string]interface{}); ok {
+		team.ID = idString(teamMap["id"])
+		if club, ok := teams.Default.Resolve(teamID(teamMap), team.Name); ok {
+			team.Club = &club
+		}
+	}
+	return team
+}
+
+// h2hGround returns a club's home ground, "" if it isn't known
+func h2hGround(team H2HTeam) string {
+	if team.Club == nil {
+		return ""
+	}
+	return team.Club.HomeVenue
+}
+
+// meetingFromMatch converts a finished match between the two given teams.
+// Both IDs must be known, or any match would do.
+func meetingFromMatch(matchMap map[string]interface{}, season, teamA, teamB string) (H2HMeeting, bool) {
+	if teamA == "" || teamB == "" {
+		return H2HMeeting{}, false
+	}
+	home := h2hTeamFromMatch(matchMap, "home")
+	away := h2hTeamFromMatch(matchMap, "away")
+
+	if !((home.ID == teamA && away.ID == teamB) || (home.ID == teamB && away.ID == teamA)) {
+		return H2HMeeting{}, false
+	}
+
+	status, ok := matchMap["status"].(map[string]interface{})
+	if !ok {
+		return H2HMeeting{}, false
+	}
+	if finished, _ := status["finished"].(bool); !finished {
+		return H2HMeeting{}, false
+	}
+	if cancelled, _ := status["cancelled"].(bool); cancelled {
+		return H2HMeeting{}, false
+	}
+
+	scoreStr, _ := status["scoreStr"].(string)
+	homeScore, awayScore, ok := parseScore(scoreStr)
+	if !ok {
+		return H2HMeeting{}, false
+	}
+
+	utcTime, _ := status["utcTime"].(string)
+	venue, venueSource := DetectVenue(matchMap)
+
+	return H2HMeeting{
+		MatchID:     idString(matchMap["id"]),
+		Season:      season,
+		UTCTime:     utcTime,
+		HomeTeamID:  home.ID,
+		HomeTeam:    home.Name,
+		AwayTeamID:  away.ID,
+		AwayTeam:    away.Name,
+		HomeScore:   homeScore,
+		AwayScore:   awayScore,
+		Venue:       venue,
+		VenueSource: venueSource,
+	}, true
+}
+
+// parseScore parses Fotmob score strings such as "2 - 1"
+func parseScore(scoreStr string) (int, int, bool) {
+	parts := strings.Split(scoreStr, "-")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	home, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, false
+	}
+	away, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return 0, 0, false
+	}
+	return home, away, true
+}
+
+// idString normalises Fotmob IDs, which arrive as either strings or numbers
+func idString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatInt(int64(v), 10)
+	default:
+		return ""
+	}
+}
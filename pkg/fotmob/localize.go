@@ -0,0 +1,125 @@
+package fotmob
+
+import (
+	"strconv"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+)
+
+// israeliLeagueName is the competition served by FetchIsraeliLeagueData
+const israeliLeagueName = "Ligat Ha'Al"
+
+// LocalizedMatch carries display strings for a match in the requested locale
+type LocalizedMatch struct {
+	Locale      i18n.Locale `json:"locale"`
+	HomeTeam    string      `json:"homeTeam"`
+	AwayTeam    string      `json:"awayTeam"`
+	Competition string      `json:"competition,omitempty"`
+	Status      string      `json:"status,omitempty"`
+	Date        string      `json:"date,omitempty"`
+	Time        string      `json:"time,omitempty"`
+}
+
+// LocalizeFixture builds display strings for a raw Fotmob league match
+func LocalizeFixture(matchMap map[string]interface{}, locale i18n.Locale) LocalizedMatch {
+	match := NewMatch(matchMap, locale)
+	localized := LocalizedMatch{
+		Locale:      locale,
+		HomeTeam:    localizedTeamName(matchMap, "home", locale),
+		AwayTeam:    localizedTeamName(matchMap, "away", locale),
+		Competition: i18n.Competition(locale, match.Competition),
+	}
+	localized.Status = i18n.Status(locale, match.Status)
+	localized.Date = match.Date
+	localized.Time = match.Time
+
+	return localized
+}
+
+// Localize returns a copy of details with a localized block, so the cached
+// value shared between requests is never modified
+func (details *MatchDetails) Localize(locale i18n.Locale) *MatchDetails {
+	localized := *details
+	localized.Localized = &LocalizedMatch{
+		Locale:      locale,
+		HomeTeam:    localizedName(locale, details.HomeTeam.ID, details.HomeTeam.Name),
+		AwayTeam:    localizedName(locale, details.AwayTeam.ID, details.AwayTeam.Name),
+		Competition: i18n.Competition(locale, details.Competition),
+		Status: i18n.Status(locale, statusKey(details.Status.Started, details.Status.Finished,
+			details.Status.Cancelled, details.Status.Short)),
+	}
+
+	if kickoff, err := ParseKickoff(details.UTCTime); err == nil {
+		localized.Localized.Date = i18n.FormatDate(locale, kickoff)
+		localized.Localized.Time = i18n.FormatTime(locale, kickoff)
+	}
+
+	return &localized
+}
+
+// Localize returns a copy of h2h with localized team names and meeting dates
+func (h2h *HeadToHead) Localize(locale i18n.Locale) *HeadToHead {
+	localized := *h2h
+	localized.Localized = &LocalizedMatch{
+		Locale:   locale,
+		HomeTeam: localizedH2HTeamName(locale, h2h.HomeTeam),
+		AwayTeam: localizedH2HTeamName(locale, h2h.AwayTeam),
+	}
+
+	localized.RecentMeetings = make([]H2HMeeting, len(h2h.RecentMeetings))
+	for i, meeting := range h2h.RecentMeetings {
+		if kickoff, err := ParseKickoff(meeting.UTCTime); err == nil {
+			meeting.Date = i18n.FormatDate(locale, kickoff)
+		}
+		localized.RecentMeetings[i] = meeting
+	}
+
+	return &localized
+}
+
+// statusKey maps Fotmob status flags to an i18n status label key
+func statusKey(started, finished, cancelled bool, reason string) string {
+	switch reason {
+	case "PP", "Postponed":
+		return "postponed"
+	case "Ab", "AB", "Abandoned":
+		return "abandoned"
+	case "HT":
+		return "halftime"
+	}
+
+	switch {
+	case cancelled:
+		return "cancelled"
+	case finished:
+		return "finished"
+	case started:
+		return "live"
+	default:
+		return "scheduled"
+	}
+}
+
+func localizedTeamName(matchMap map[string]interface{}, side string, locale i18n.Locale) string {
+	name := getTeamName(matchMap, side)
+	id := 0
+	if team, ok := matchMap[side].(map[string]interface{}); ok {
+		id = teamID(team)
+	}
+	return localizedName(locale, id, name)
+}
+
+func localizedH2HTeamName(locale i18n.Locale, team H2HTeam) string {
+	id, _ := strconv.Atoi(team.ID)
+	return localizedName(locale, id, team.Name)
+}
+
+// localizedName looks the team up in the registry, falling back to the
+// upstream name for clubs we don't know
+func localizedName(locale i18n.Locale, id int, name string) string {
+	if club, ok := teams.Default.Resolve(id, name); ok {
+		return i18n.TeamName(locale, club)
+	}
+	return name
+}
@@ -0,0 +1,177 @@
+package fotmob
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+)
+
+// Match is our typed view of a Fotmob fixture. Kickoff is the source of
+// truth; the date and time strings are derived from it in Asia/Jerusalem.
+type Match struct {
+	ID              int        `json:"id"`
+	HomeTeam        string     `json:"homeTeam"`
+	HomeTeamLogo    string     `json:"homeTeamLogo"`
+	AwayTeam        string     `json:"awayTeam"`
+	AwayTeamLogo    string     `json:"awayTeamLogo"`
+	HomeScore       *int       `json:"homeScore"`
+	AwayScore       *int       `json:"awayScore"`
+	Kickoff         *time.Time `json:"kickoff"`
+	KickoffLocal    string     `json:"kickoffLocal,omitempty"`
+	Date            string     `json:"date"`
+	Time            string     `json:"time"`
+	TimeTBC         bool       `json:"timeTbc"`
+	Competition     string     `json:"competition"`
+	CompetitionLogo string     `json:"competitionLogo"`
+	Status          string     `json:"status"`
+	Round           string     `json:"round"`
+	Venue           string     `json:"venue"`
+	// VenueSource says how Venue was decided; see DetectVenue
+	VenueSource string `json:"venueSource"`
+	// Source names the provider the fixture came from, e.g. "fotmob"
+	Source string `json:"source"`
+	// Overridden lists the fields an admin override corrected
+	Overridden []string `json:"overridden,omitempty"`
+}
+
+// kickoffLayouts are the RFC 3339 variants Fotmob has been seen to send:
+// with or without fractional seconds, with "Z" or a numeric offset, and
+// occasionally with no zone at all (which Fotmob means as UTC)
+var kickoffLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04",
+}
+
+// ParseKickoff parses a Fotmob kickoff timestamp into UTC
+func ParseKickoff(value string) (time.Time, error) {
+	// RFC 3339 allows lowercase separators and a space instead of "T"
+	normalized := strings.ToUpper(strings.TrimSpace(value))
+	if len(normalized) > 10 && normalized[10] == ' ' {
+		normalized = normalized[:10] + "T" + normalized[11:]
+	}
+
+	for _, layout := range kickoffLayouts {
+		if t, err := time.Parse(layout, normalized); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised kickoff time %q", value)
+}
+
+// matchKickoff returns a raw Fotmob match's kickoff, reading status.utcTime
+// and falling back to the timeTS epoch seconds
+func matchKickoff(matchMap map[string]interface{}) (time.Time, bool) {
+	if status, ok := matchMap["status"].(map[string]interface{}); ok {
+		if utcTimeStr, ok := status["utcTime"].(string); ok && utcTimeStr != "" {
+			kickoff, err := ParseKickoff(utcTimeStr)
+			return kickoff, err == nil
+		}
+	}
+
+	if tsFloat, ok := matchMap["timeTS"].(float64); ok && tsFloat > 0 {
+		// timeTS has been sent in both seconds and milliseconds
+		if tsFloat > 1e12 {
+			return time.UnixMilli(int64(tsFloat)).UTC(), true
+		}
+		return time.Unix(int64(tsFloat), 0).UTC(), true
+	}
+
+	return time.Time{}, false
+}
+
+// isTimeTBC reports whether a raw Fotmob match has a date but no confirmed
+// kickoff time yet
+func isTimeTBC(matchMap map[string]interface{}) bool {
+	if tbd, ok := matchMap["timeTBD"].(bool); ok && tbd {
+		return true
+	}
+
+	status, ok := matchMap["status"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	if tbd, ok := status["timeTBD"].(bool); ok && tbd {
+		return true
+	}
+	if reason, ok := status["reason"].(map[string]interface{}); ok {
+		short, _ := reason["short"].(string)
+		switch strings.ToUpper(short) {
+		case "TBC", "TBD", "TBA":
+			return true
+		}
+	}
+	return false
+}
+
+// NewMatch converts a raw Fotmob league match into a Match, with the date and
+// time rendered for locale. Time is left empty for TBC fixtures.
+func NewMatch(matchMap map[string]interface{}, locale i18n.Locale) Match {
+	match := Match{
+		HomeTeam:    getTeamName(matchMap, "home"),
+		AwayTeam:    getTeamName(matchMap, "away"),
+		Competition: israeliLeagueName,
+		TimeTBC:     isTimeTBC(matchMap),
+		Source:      "fotmob",
+	}
+	if source, ok := matchMap["source"].(string); ok && source != "" {
+		match.Source = source
+	}
+	if competition, ok := matchMap["competition"].(string); ok && competition != "" {
+		match.Competition = competition
+	}
+	match.ID, _ = strconv.Atoi(idString(matchMap["id"]))
+
+	if home, ok := matchMap["home"].(map[string]interface{}); ok {
+		if id := teamID(home); id != 0 {
+			match.HomeTeamLogo = teams.LogoURL(id)
+		}
+	}
+	if away, ok := matchMap["away"].(map[string]interface{}); ok {
+		if id := teamID(away); id != 0 {
+			match.AwayTeamLogo = teams.LogoURL(id)
+		}
+	}
+
+	if round, ok := matchMap["round"]; ok {
+		match.Round = fmt.Sprint(round)
+	}
+	match.Venue, match.VenueSource = DetectVenue(matchMap)
+	match.Overridden, _ = matchMap["overridden"].([]string)
+
+	status, _ := matchMap["status"].(map[string]interface{})
+	if status != nil {
+		started, _ := status["started"].(bool)
+		finished, _ := status["finished"].(bool)
+		cancelled, _ := status["cancelled"].(bool)
+		reason := ""
+		if reasonMap, ok := status["reason"].(map[string]interface{}); ok {
+			reason, _ = reasonMap["short"].(string)
+		}
+		match.Status = statusKey(started, finished, cancelled, reason)
+
+		if scoreStr, ok := status["scoreStr"].(string); ok {
+			if home, away, ok := parseScore(scoreStr); ok {
+				match.HomeScore, match.AwayScore = &home, &away
+			}
+		}
+	}
+	if match.TimeTBC && match.Status == "scheduled" {
+		match.Status = "tbc"
+	}
+
+	if kickoff, ok := matchKickoff(matchMap); ok {
+		match.Kickoff = &kickoff
+		match.Date = i18n.FormatDate(locale, kickoff)
+		if !match.TimeTBC {
+			match.KickoffLocal = kickoff.In(i18n.Jerusalem).Format(time.RFC3339)
+			match.Time = i18n.FormatTime(locale, kickoff)
+		}
+	}
+
+	return match
+}
@@ -0,0 +1,561 @@
+package fotmob
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/cache"
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+)
+
+// Cache lifetimes for match details depend on how likely the data is to change
+const (
+	finishedMatchTTL = 24 * time.Hour
+	liveMatchTTL     = 30 * time.Second
+	upcomingMatchTTL = 10 * time.Minute
+	// Details from a fallback provider lack lineups and events, so they
+	// give way quickly to Fotmob's once it answers again
+	fallbackDetailsTTL = time.Minute
+)
+
+var matchDetailsCache = cache.New()
+
+// MatchDetailsCached reports whether fresh details for matchID are cached
+func MatchDetailsCached(matchID int) bool {
+	_, ok := matchDetailsCache.Get(strconv.Itoa(matchID))
+	return ok
+}
+
+// MatchDetails is the typed view of a Fotmob matchDetails response
+type MatchDetails struct {
+	ID            int                 `json:"id"`
+	Competition   string              `json:"competition"`
+	CompetitionID int                 `json:"competitionId"`
+	Round         string              `json:"round"`
+	UTCTime       string              `json:"utcTime"`
+	Status        MatchStatus         `json:"status"`
+	HomeTeam      MatchTeam           `json:"homeTeam"`
+	AwayTeam      MatchTeam           `json:"awayTeam"`
+	Venue         *MatchVenue         `json:"venue,omitempty"`
+	Referee       string              `json:"referee,omitempty"`
+	Attendance    int                 `json:"attendance,omitempty"`
+	Lineups       MatchLineups        `json:"lineups"`
+	Goals         []GoalEvent         `json:"goals"`
+	Cards         []CardEvent         `json:"cards"`
+	Substitutions []SubstitutionEvent `json:"substitutions"`
+	Stats         []StatGroup         `json:"stats"`
+	Localized     *LocalizedMatch     `json:"localized,omitempty"`
+	// Source names the provider the details came from, e.g. "fotmob"
+	Source string `json:"source"`
+	// Overridden lists the fields an admin override corrected
+	Overridden []string `json:"overridden,omitempty"`
+}
+
+// MatchStatus describes where a match is in its lifecycle
+type MatchStatus struct {
+	Started   bool   `json:"started"`
+	Finished  bool   `json:"finished"`
+	Cancelled bool   `json:"cancelled"`
+	Score     string `json:"score,omitempty"`
+	Short     string `json:"short,omitempty"`
+	Long      string `json:"long,omitempty"`
+	LiveTime  string `json:"liveTime,omitempty"`
+}
+
+// IsLive reports whether the match is currently being played
+func (s MatchStatus) IsLive() bool {
+	return s.Started && !s.Finished && !s.Cancelled
+}
+
+// MatchTeam is one side of a match
+type MatchTeam struct {
+	ID      int         `json:"id"`
+	Name    string      `json:"name"`
+	Score   *int        `json:"score"`
+	LogoURL string      `json:"logoUrl,omitempty"`
+	Club    *teams.Team `json:"club,omitempty"`
+}
+
+// MatchVenue is the stadium a match is played at, as reported by Fotmob
+type MatchVenue struct {
+	Name      string  `json:"name"`
+	City      string  `json:"city,omitempty"`
+	Country   string  `json:"country,omitempty"`
+	Latitude  float64 `json:"lat,omitempty"`
+	Longitude float64 `json:"long,omitempty"`
+	Capacity  int     `json:"capacity,omitempty"`
+}
+
+// MatchLineups holds both teams' lineups
+type MatchLineups struct {
+	Home *TeamLineup `json:"home,omitempty"`
+	Away *TeamLineup `json:"away,omitempty"`
+}
+
+// TeamLineup is a single team's starting eleven, bench and coach
+type TeamLineup struct {
+	TeamID    int            `json:"teamId"`
+	TeamName  string         `json:"teamName"`
+	Formation string         `json:"formation,omitempty"`
+	Coach     string         `json:"coach,omitempty"`
+	Starters  []LineupPlayer `json:"starters"`
+	Subs      []LineupPlayer `json:"subs"`
+}
+
+// LineupPlayer is a player listed in a lineup
+type LineupPlayer struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	ShirtNumber string `json:"shirtNumber,omitempty"`
+	PositionID  int    `json:"positionId,omitempty"`
+	IsCaptain   bool   `json:"isCaptain,omitempty"`
+}
+
+// GoalEvent is a goal scored during the match
+type GoalEvent struct {
+	Minute     int    `json:"minute"`
+	AddedTime  int    `json:"addedTime,omitempty"`
+	IsHome     bool   `json:"isHome"`
+	PlayerID   int    `json:"playerId"`
+	PlayerName string `json:"playerName"`
+	AssistName string `json:"assistName,omitempty"`
+	OwnGoal    bool   `json:"ownGoal"`
+	Penalty    bool   `json:"penalty"`
+	ScoreAfter []int  `json:"scoreAfter,omitempty"`
+}
+
+// CardEvent is a yellow or red card shown during the match
+type CardEvent struct {
+	Minute     int    `json:"minute"`
+	AddedTime  int    `json:"addedTime,omitempty"`
+	IsHome     bool   `json:"isHome"`
+	PlayerID   int    `json:"playerId"`
+	PlayerName string `json:"playerName"`
+	Card       string `json:"card"`
+}
+
+// SubstitutionEvent is a player change during the match
+type SubstitutionEvent struct {
+	Minute      int    `json:"minute"`
+	AddedTime   int    `json:"addedTime,omitempty"`
+	IsHome      bool   `json:"isHome"`
+	PlayerIn    string `json:"playerIn"`
+	PlayerInID  int    `json:"playerInId"`
+	PlayerOut   string `json:"playerOut"`
+	PlayerOutID int    `json:"playerOutId"`
+}
+
+// StatGroup is a titled group of team statistics (e.g. "Top stats", "Shots")
+type StatGroup struct {
+	Title string     `json:"title"`
+	Key   string     `json:"key"`
+	Stats []TeamStat `json:"stats"`
+}
+
+// TeamStat is a single statistic compared between home and away
+type TeamStat struct {
+	Title string `json:"title"`
+	Key   string `json:"key"`
+	Home  string `json:"home"`
+	Away  string `json:"away"`
+}
+
+// flexString decodes JSON values Fotmob sends either as strings or numbers
+type flexString string
+
+func (f *flexString) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*f = ""
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*f = flexString(s)
+		return nil
+	}
+	*f = flexString(data)
+	return nil
+}
+
+// Int converts the value to an int, returning 0 when it isn't numeric
+func (f flexString) Int() int {
+	n, err := strconv.Atoi(string(f))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// Raw Fotmob matchDetails payload, limited to the fields we use
+type fotmobMatchDetails struct {
+	General struct {
+		MatchID         flexString `json:"matchId"`
+		LeagueID        flexString `json:"leagueId"`
+		LeagueName      string     `json:"leagueName"`
+		LeagueRoundName string     `json:"leagueRoundName"`
+		MatchRound      flexString `json:"matchRound"`
+		MatchTimeUTC    string     `json:"matchTimeUTCDate"`
+	} `json:"general"`
+	Header struct {
+		Teams []struct {
+			ID       flexString `json:"id"`
+			Name     string     `json:"name"`
+			Score    *int       `json:"score"`
+			ImageURL string     `json:"imageUrl"`
+		} `json:"teams"`
+		Status struct {
+			UTCTime   string `json:"utcTime"`
+			Started   bool   `json:"started"`
+			Finished  bool   `json:"finished"`
+			Cancelled bool   `json:"cancelled"`
+			ScoreStr  string `json:"scoreStr"`
+			Reason    struct {
+				Short string `json:"short"`
+				Long  string `json:"long"`
+			} `json:"reason"`
+			LiveTime struct {
+				Short string `json:"short"`
+			} `json:"liveTime"`
+		} `json:"status"`
+	} `json:"header"`
+	Content struct {
+		MatchFacts struct {
+			Events struct {
+				Events []fotmobEvent `json:"events"`
+			} `json:"events"`
+			InfoBox struct {
+				Stadium *struct {
+					Name     string  `json:"name"`
+					City     string  `json:"city"`
+					Country  string  `json:"country"`
+					Lat      float64 `json:"lat"`
+					Long     float64 `json:"long"`
+					Capacity int     `json:"capacity"`
+				} `json:"Stadium"`
+				Referee *struct {
+					Text string `json:"text"`
+				} `json:"Referee"`
+				Attendance int `json:"Attendance"`
+			} `json:"infoBox"`
+		} `json:"matchFacts"`
+		Lineup struct {
+			HomeTeam *fotmobLineupTeam `json:"homeTeam"`
+			AwayTeam *fotmobLineupTeam `json:"awayTeam"`
+		} `json:"lineup"`
+		Stats *struct {
+			Periods struct {
+				All struct {
+					Stats []struct {
+						Title string `json:"title"`
+						Key   string `json:"key"`
+						Stats []struct {
+							Title string        `json:"title"`
+							Key   string        `json:"key"`
+							Stats []interface{} `json:"stats"`
+						} `json:"stats"`
+					} `json:"stats"`
+				} `json:"All"`
+			} `json:"Periods"`
+		} `json:"stats"`
+	} `json:"content"`
+}
+
+type fotmobEvent struct {
+	Type         string `json:"type"`
+	Time         int    `json:"time"`
+	OverloadTime *int   `json:"overloadTime"`
+	IsHome       bool   `json:"isHome"`
+	Player       *struct {
+		ID   flexString `json:"id"`
+		Name string     `json:"name"`
+	} `json:"player"`
+	NameStr     string `json:"nameStr"`
+	Card        string `json:"card"`
+	OwnGoal     *bool  `json:"ownGoal"`
+	GoalDesc    string `json:"goalDescription"`
+	AssistInput string `json:"assistInput"`
+	NewScore    []int  `json:"newScore"`
+	Swap        []struct {
+		ID   flexString `json:"id"`
+		Name string     `json:"name"`
+	} `json:"swap"`
+}
+
+type fotmobLineupTeam struct {
+	ID        flexString           `json:"id"`
+	Name      string               `json:"name"`
+	Formation string               `json:"formation"`
+	Starters  []fotmobLineupPlayer `json:"starters"`
+	Subs      []fotmobLineupPlayer `json:"subs"`
+	Coach     *fotmobLineupPlayer  `json:"coach"`
+}
+
+type fotmobLineupPlayer struct {
+	ID          flexString `json:"id"`
+	Name        string     `json:"name"`
+	ShirtNumber flexString `json:"shirtNumber"`
+	PositionID  flexString `json:"positionId"`
+	IsCaptain   bool       `json:"isCaptain"`
+}
+
+// FetchMatchDetails fetches lineups, events and statistics for a single match
+func (c *FotmobClient) FetchMatchDetails(ctx context.Context, matchID int) (*MatchDetails, error) {
+	url := fmt.Sprintf("https://www.fotmob.com/api/matchDetails?matchId=%d", matchID)
+	body, err := c.makeRequest(ctx, url, c.timeouts.MatchDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw fotmobMatchDetails
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("error parsing match details: %v", err)
+	}
+
+	if raw.General.MatchID == "" && len(raw.Header.Teams) == 0 {
+		return nil, fmt.Errorf("%w: match %d", ErrMatchNotFound, matchID)
+	}
+
+	return convertMatchDetails(matchID, &raw), nil
+}
+
+// MatchDetailsFetcher fetches details for a match, e.g. a FotmobClient or a
+// chain of fixture providers
+type MatchDetailsFetcher interface {
+	FetchMatchDetails(ctx context.Context, matchID int) (*MatchDetails, error)
+}
+
+// GetMatchDetails returns match details from the cache, fetching them when
+// missing or expired. Finished matches are cached much longer than live ones.
+func GetMatchDetails(ctx context.Context, client MatchDetailsFetcher, matchID int) (*MatchDetails, error) {
+	key := strconv.Itoa(matchID)
+	if cached, ok := matchDetailsCache.Get(key); ok {
+		if _, ok := venueCache.Get(key); !ok {
+			rememberVenue(cached.(*MatchDetails))
+		}
+		return cached.(*MatchDetails), nil
+	}
+
+	details, err := client.FetchMatchDetails(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	matchDetailsCache.Set(key, details, MatchDetailsTTL(details))
+	rememberVenue(details)
+	return details, nil
+}
+
+// MatchDetailsTTL picks a cache lifetime based on where the details came
+// from and the match status
+func MatchDetailsTTL(details *MatchDetails) time.Duration {
+	switch {
+	case details.Source != "fotmob":
+		return fallbackDetailsTTL
+	case details.Status.Finished || details.Status.Cancelled:
+		return finishedMatchTTL
+	case details.Status.IsLive():
+		return liveMatchTTL
+	default:
+		return upcomingMatchTTL
+	}
+}
+
+func convertMatchDetails(matchID int, raw *fotmobMatchDetails) *MatchDetails {
+	status := raw.Header.Status
+	details := &MatchDetails{
+		ID:            matchID,
+		Competition:   raw.General.LeagueName,
+		CompetitionID: raw.General.LeagueID.Int(),
+		Round:         raw.General.LeagueRoundName,
+		UTCTime:       status.UTCTime,
+		Source:        "fotmob",
+		Status: MatchStatus{
+			Started:   status.Started,
+			Finished:  status.Finished,
+			Cancelled: status.Cancelled,
+			Score:     status.ScoreStr,
+			Short:     status.Reason.Short,
+			Long:      status.Reason.Long,
+			LiveTime:  status.LiveTime.Short,
+		},
+		Goals:         []GoalEvent{},
+		Cards:         []CardEvent{},
+		Substitutions: []SubstitutionEvent{},
+		Stats:         []StatGroup{},
+	}
+
+	if details.Round == "" {
+		details.Round = string(raw.General.MatchRound)
+	}
+	if details.UTCTime == "" {
+		details.UTCTime = raw.General.MatchTimeUTC
+	}
+
+	// Header teams are always ordered home, away
+	for i, team := range raw.Header.Teams {
+		converted := MatchTeam{
+			ID:      team.ID.Int(),
+			Name:    team.Name,
+			Score:   team.Score,
+			LogoURL: team.ImageURL,
+		}
+		if club, ok := teams.Default.Resolve(converted.ID, converted.Name); ok {
+			converted.Club = &club
+		}
+		if i == 0 {
+			details.HomeTeam = converted
+		} else if i == 1 {
+			details.AwayTeam = converted
+		}
+	}
+
+	infoBox := raw.Content.MatchFacts.InfoBox
+	if infoBox.Stadium != nil && infoBox.Stadium.Name != "" {
+		details.Venue = &MatchVenue{
+			Name:      infoBox.Stadium.Name,
+			City:      infoBox.Stadium.City,
+			Country:   infoBox.Stadium.Country,
+			Latitude:  infoBox.Stadium.Lat,
+			Longitude: infoBox.Stadium.Long,
+			Capacity:  infoBox.Stadium.Capacity,
+		}
+	}
+	if infoBox.Referee != nil {
+		details.Referee = infoBox.Referee.Text
+	}
+	details.Attendance = infoBox.Attendance
+
+	details.Lineups.Home = convertLineup(raw.Content.Lineup.HomeTeam)
+	details.Lineups.Away = convertLineup(raw.Content.Lineup.AwayTeam)
+
+	for _, event := range raw.Content.MatchFacts.Events.Events {
+		addedTime := 0
+		if event.OverloadTime != nil {
+			addedTime = *event.OverloadTime
+		}
+
+		playerID, playerName := 0, event.NameStr
+		if event.Player != nil {
+			playerID = event.Player.ID.Int()
+			if event.Player.Name != "" {
+				playerName = event.Player.Name
+			}
+		}
+
+		switch event.Type {
+		case "Goal":
+			details.Goals = append(details.Goals, GoalEvent{
+				Minute:     event.Time,
+				AddedTime:  addedTime,
+				IsHome:     event.IsHome,
+				PlayerID:   playerID,
+				PlayerName: playerName,
+				AssistName: strings.TrimPrefix(event.AssistInput, "assist by "),
+				OwnGoal:    event.OwnGoal != nil && *event.OwnGoal,
+				Penalty:    strings.EqualFold(event.GoalDesc, "Penalty"),
+				ScoreAfter: event.NewScore,
+			})
+		case "Card":
+			details.Cards = append(details.Cards, CardEvent{
+				Minute:     event.Time,
+				AddedTime:  addedTime,
+				IsHome:     event.IsHome,
+				PlayerID:   playerID,
+				PlayerName: playerName,
+				Card:       event.Card,
+			})
+		case "Substitution":
+			// Fotmob lists the incoming player first, then the outgoing one
+			if len(event.Swap) < 2 {
+				continue
+			}
+			details.Substitutions = append(details.Substitutions, SubstitutionEvent{
+				Minute:      event.Time,
+				AddedTime:   addedTime,
+				IsHome:      event.IsHome,
+				PlayerIn:    event.Swap[0].Name,
+				PlayerInID:  event.Swap[0].ID.Int(),
+				PlayerOut:   event.Swap[1].Name,
+				PlayerOutID: event.Swap[1].ID.Int(),
+			})
+		}
+	}
+
+	if raw.Content.Stats != nil {
+		for _, group := range raw.Content.Stats.Periods.All.Stats {
+			statGroup := StatGroup{
+				Title: group.Title,
+				Key:   group.Key,
+				Stats: []TeamStat{},
+			}
+			for _, stat := range group.Stats {
+				// Group headers come through as entries without values
+				if len(stat.Stats) < 2 {
+					continue
+				}
+				statGroup.Stats = append(statGroup.Stats, TeamStat{
+					Title: stat.Title,
+					Key:   stat.Key,
+					Home:  formatStatValue(stat.Stats[0]),
+					Away:  formatStatValue(stat.Stats[1]),
+				})
+			}
+			details.Stats = append(details.Stats, statGroup)
+		}
+	}
+
+	return details
+}
+
+func convertLineup(team *fotmobLineupTeam) *TeamLineup {
+	if team == nil {
+		return nil
+	}
+
+	lineup := &TeamLineup{
+		TeamID:    team.ID.Int(),
+		TeamName:  team.Name,
+		Formation: team.Formation,
+		Starters:  convertLineupPlayers(team.Starters),
+		Subs:      convertLineupPlayers(team.Subs),
+	}
+	if team.Coach != nil {
+		lineup.Coach = team.Coach.Name
+	}
+	return lineup
+}
+
+func convertLineupPlayers(players []fotmobLineupPlayer) []LineupPlayer {
+	converted := make([]LineupPlayer, 0, len(players))
+	for _, p := range players {
+		converted = append(converted, LineupPlayer{
+			ID:          p.ID.Int(),
+			Name:        p.Name,
+			ShirtNumber: string(p.ShirtNumber),
+			PositionID:  p.PositionID.Int(),
+			IsCaptain:   p.IsCaptain,
+		})
+	}
+	return converted
+}
+
+// formatStatValue renders a stat value that may be a number, string or null
+func formatStatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,298 @@
+package fotmob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/outbound"
+)
+
+// ErrCircuitOpen is returned without calling Fotmob while the circuit
+// breaker is open
+var ErrCircuitOpen = errors.New("Fotmob circuit breaker is open")
+
+// StatusError is a non-2xx answer from Fotmob other than a token rejection
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return "Fotmob answered " + e.Status
+}
+
+// RetryPolicy retries failed GETs with exponential backoff and full jitter:
+// the n-th retry waits a random time up to BaseDelay*2^(n-1), capped at
+// MaxDelay
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// BreakerPolicy opens the circuit after Threshold consecutive failures.
+// After Cooldown one probe request is let through (half-open); it closes the
+// circuit on success and reopens it on failure.
+type BreakerPolicy struct {
+	Threshold int
+	Cooldown  time.Duration
+}
+
+// Resilience configures how the client handles a flaky upstream
+type Resilience struct {
+	Retry   RetryPolicy
+	Breaker BreakerPolicy
+}
+
+// DefaultResilience is used by NewFotmobClient
+var DefaultResilience = Resilience{
+	Retry:   RetryPolicy{MaxAttempts: 3, BaseDelay: 250 * time.Millisecond, MaxDelay: 2 * time.Second},
+	Breaker: BreakerPolicy{Threshold: 5, Cooldown: 30 * time.Second},
+}
+
+// backoff returns how long to wait before the given retry (1 for the first)
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	ceiling := p.BaseDelay << (retry - 1)
+	if ceiling > p.MaxDelay || ceiling <= 0 {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// retryable reports whether a failed attempt is worth repeating. Token
+// rejections and client errors won't get better by asking again, and a
+// caller that went away doesn't want an answer.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || IsTokenError(err) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == 429
+	}
+	return true
+}
+
+// countsAgainstBreaker reports whether a failure says something about
+// Fotmob's health. Token problems are handled by degraded mode instead.
+func countsAgainstBreaker(err error) bool {
+	if err == nil || IsTokenError(err) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == 429
+	}
+	return true
+}
+
+// Breaker states
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// BreakerStatus is a snapshot of the circuit breaker
+type BreakerStatus struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	OpenedAt            *time.Time `json:"openedAt,omitempty"`
+	ProbeAt             *time.Time `json:"probeAt,omitempty"`
+	Trips               int        `json:"trips"`
+	ShortCircuited      int        `json:"shortCircuited"`
+}
+
+// breaker is a consecutive-failure circuit breaker
+type breaker struct {
+	mu       sync.Mutex
+	policy   BreakerPolicy
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+	trips    int
+	rejected int
+}
+
+func newBreaker(policy BreakerPolicy) *breaker {
+	return &breaker{policy: policy, state: BreakerClosed}
+}
+
+// allow reports whether a request may go out. Once the cooldown has passed
+// it moves to half-open and lets a single probe through.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.policy.Cooldown {
+			b.rejected++
+			return false
+		}
+		log.Printf("Fotmob circuit breaker half-open: probing after %v", b.policy.Cooldown)
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			b.rejected++
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// release returns an allowed request that never went out, so a half-open
+// breaker can let another probe through
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerHalfOpen {
+		b.probing = false
+	}
+}
+
+// record feeds the outcome of an allowed request back into the breaker
+func (b *breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen {
+		b.probing = false
+	}
+
+	if !countsAgainstBreaker(err) {
+		if err != nil && b.state == BreakerHalfOpen {
+			// The probe told us nothing about Fotmob; let the next one try
+			return
+		}
+		if b.state != BreakerClosed {
+			log.Printf("Fotmob circuit breaker closed: probe succeeded after %v open", time.Since(b.openedAt).Round(time.Second))
+		}
+		b.state = BreakerClosed
+		b.failures = 0
+		b.openedAt = time.Time{}
+		return
+	}
+
+	b.failures++
+	switch {
+	case b.state == BreakerHalfOpen:
+		log.Printf("Fotmob circuit breaker reopened: probe failed: %v", err)
+		b.open()
+	case b.state == BreakerClosed && b.failures >= b.policy.Threshold:
+		log.Printf("Fotmob circuit breaker opened after %d consecutive failures, cooling down for %v: %v", b.failures, b.policy.Cooldown, err)
+		b.open()
+	}
+}
+
+func (b *breaker) open() {
+	b.state = BreakerOpen
+	b.openedAt = time.Now()
+	b.trips++
+}
+
+// retryAfter is how long until the breaker lets a probe through
+func (b *breaker) retryAfter() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != BreakerOpen {
+		return 0
+	}
+	return b.policy.Cooldown - time.Since(b.openedAt)
+}
+
+func (b *breaker) status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := BreakerStatus{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		Trips:               b.trips,
+		ShortCircuited:      b.rejected,
+	}
+	if !b.openedAt.IsZero() {
+		openedAt := b.openedAt
+		probeAt := openedAt.Add(b.policy.Cooldown)
+		status.OpenedAt = &openedAt
+		status.ProbeAt = &probeAt
+	}
+	return status
+}
+
+// Breaker returns a snapshot of the client's circuit breaker
+func (c *FotmobClient) Breaker() BreakerStatus {
+	return c.breaker.status()
+}
+
+// BreakerRetryAfter is how long until an open circuit lets a probe through,
+// zero when it isn't open
+func (c *FotmobClient) BreakerRetryAfter() time.Duration {
+	return c.breaker.retryAfter()
+}
+
+// makeRequest GETs url through the outbound limiter and the circuit breaker,
+// retrying transient failures. timeout bounds each attempt.
+func (c *FotmobClient) makeRequest(ctx context.Context, url string, timeout time.Duration) ([]byte, error) {
+	attempts := c.resilience.Retry.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			wait := c.resilience.Retry.backoff(attempt - 1)
+			log.Printf("Retrying Fotmob request in %v (attempt %d/%d): %v", wait.Round(time.Millisecond), attempt, attempts, lastErr)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		// The breaker comes first so short-circuited calls fail fast and
+		// don't use up the outbound budget
+		if !c.breaker.allow() {
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w (last error: %v)", ErrCircuitOpen, lastErr)
+			}
+			return nil, ErrCircuitOpen
+		}
+
+		// A request the limiter holds back didn't probe anything
+		if err := outbound.Wait(ctx, "fotmob"); err != nil {
+			c.breaker.release()
+			if lastErr != nil && errors.Is(err, outbound.ErrBudgetExhausted) {
+				return nil, fmt.Errorf("%w (last error: %v)", err, lastErr)
+			}
+			return nil, err
+		}
+
+		body, err := c.doRequest(ctx, url, timeout)
+		c.breaker.record(err)
+		if err == nil {
+			return body, nil
+		}
+		lastErr = err
+		if !retryable(ctx, err) || c.breaker.retryAfter() > 0 {
+			// Once the circuit is open, retrying would only short-circuit
+			break
+		}
+	}
+	return nil, lastErr
+}
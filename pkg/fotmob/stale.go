@@ -0,0 +1,96 @@
+package fotmob
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/api"
+)
+
+// ErrTokenRejected means Fotmob refused the x-mas token we sent
+var ErrTokenRejected = errors.New("Fotmob rejected the x-mas token")
+
+// IsTokenError reports whether err means a fresh token is needed
+func IsTokenError(err error) bool {
+	return errors.Is(err, api.ErrNoToken) || errors.Is(err, ErrTokenRejected)
+}
+
+var leagueFile = filepath.Join("responses", "league_127.json")
+
+// storeLeagueData keeps the last good league response on disk so it can be
+// served while no valid token is available
+func storeLeagueData(body []byte) {
+	os.MkdirAll(filepath.Dir(leagueFile), 0755)
+
+	tmp := leagueFile + ".tmp"
+	if err := ioutil.WriteFile(tmp, body, 0644); err != nil {
+		log.Printf("Warning: Failed to store league data: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, leagueFile); err != nil {
+		log.Printf("Warning: Failed to store league data: %v", err)
+	}
+}
+
+// StoredLeagueData returns the last league response fetched successfully and
+// when it was fetched
+func StoredLeagueData() (map[string]interface{}, time.Time, error) {
+	info, err := os.Stat(leagueFile)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	data, err := ioutil.ReadFile(leagueFile)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, time.Time{}, fmt.Errorf("stored league data is corrupt: %v", err)
+	}
+
+	return result, info.ModTime(), nil
+}
+
+// StoredLeagueMatches returns the raw matches of the stored league data, to
+// be filtered like fresh ones. The returned time is when that data was
+// fetched.
+func StoredLeagueMatches() ([]interface{}, time.Time, error) {
+	leagueData, fetchedAt, err := StoredLeagueData()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	matches, err := extractAllMatches(leagueData)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return matches, fetchedAt, nil
+}
+
+// StaleMatchDetails returns cached details for matchID even if they expired
+func StaleMatchDetails(matchID int) (*MatchDetails, time.Time, bool) {
+	entry, ok := matchDetailsCache.GetEntry(strconv.Itoa(matchID))
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	return entry.Value.(*MatchDetails), entry.FetchedAt, true
+}
+
+// StaleHeadToHead returns a cached head-to-head summary even if it expired
+func StaleHeadToHead(matchID int) (*HeadToHead, time.Time, bool) {
+	entry, ok := h2hCache.GetEntry(strconv.Itoa(matchID))
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	return entry.Value.(*HeadToHead), entry.FetchedAt, true
+}
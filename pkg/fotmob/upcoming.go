@@ -0,0 +1,76 @@
+package fotmob
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+)
+
+// FetchUpcomingSammyOferMatches returns the league's upcoming fixtures at Sammy
+// Ofer Stadium. Each raw match is annotated with registry clubs, a typed
+// "match" and a "localized" block for locale.
+func (c *FotmobClient) FetchUpcomingSammyOferMatches(ctx context.Context, locale i18n.Locale) ([]interface{}, error) {
+	// Record the start time for performance tracking
+	startTime := time.Now()
+	
+	matchesData, err := c.FetchIsraeliLeagueMatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	log.Printf("Successfully fetched matches data in %v", time.Since(startTime))
+	
+	matchesArr, ok := matchesData.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid matches data format")
+	}
+	
+	log.Printf("Processing %d total matches from API", len(matchesArr))
+	upcomingMatches := UpcomingSammyOferMatches(matchesArr, locale)
+
+	log.Printf("Found %d upcoming Sammy Ofer matches (request took %v)", 
+		len(upcomingMatches), time.Since(startTime))
+	
+	return upcomingMatches, nil
+}
+
+// UpcomingSammyOferMatches filters raw league matches, from Fotmob or any
+// provider using its shape, down to future games at Sammy Ofer and annotates
+// them
+func UpcomingSammyOferMatches(matchesArr []interface{}, locale i18n.Locale) []interface{} {
+	filteredMatches := FilterSammyOferMatches(matchesArr)
+	
+	// Get all upcoming matches
+	now := time.Now().UTC()
+	log.Printf("Current time (UTC): %s", now.Format(time.RFC3339))
+	upcomingMatches := []interface{}{}
+	
+	for _, match := range filteredMatches {
+		matchMap, ok := match.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		
+		matchTime, ok := matchKickoff(matchMap)
+		if !ok {
+			continue
+		}
+		
+		// Include only future matches
+		if matchTime.After(now) {
+			log.Printf("Found upcoming match: %s vs %s on %s", 
+				getTeamName(matchMap, "home"),
+				getTeamName(matchMap, "away"),
+				matchTime.Format(time.RFC1123))
+			AnnotateClubs(matchMap)
+			matchMap["match"] = NewMatch(matchMap, locale)
+			matchMap["localized"] = LocalizeFixture(matchMap, locale)
+			upcomingMatches = append(upcomingMatches, match)
+		}
+	}
+	
+	return upcomingMatches
+}
@@ -0,0 +1,52 @@
+package fotmob
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// UpstreamStats summarises requests made to the Fotmob API since startup
+type UpstreamStats struct {
+	Requests     int            `json:"requests"`
+	Errors       int            `json:"errors"`
+	ErrorsByKind map[string]int `json:"errorsByKind"`
+	LastError    string         `json:"lastError,omitempty"`
+	LastErrorAt  *time.Time     `json:"lastErrorAt,omitempty"`
+}
+
+var upstream = struct {
+	sync.Mutex
+	stats UpstreamStats
+}{stats: UpstreamStats{ErrorsByKind: make(map[string]int)}}
+
+// recordUpstream counts a request. kind is empty for successes, otherwise a
+// short label such as "network" or "http_403".
+func recordUpstream(url, kind string, err error) {
+	upstream.Lock()
+	defer upstream.Unlock()
+
+	upstream.stats.Requests++
+	if kind == "" {
+		return
+	}
+
+	now := time.Now()
+	upstream.stats.Errors++
+	upstream.stats.ErrorsByKind[kind]++
+	upstream.stats.LastError = fmt.Sprintf("%s: %v", url, err)
+	upstream.stats.LastErrorAt = &now
+}
+
+// Upstream returns a snapshot of the upstream request counters
+func Upstream() UpstreamStats {
+	upstream.Lock()
+	defer upstream.Unlock()
+
+	stats := upstream.stats
+	stats.ErrorsByKind = make(map[string]int, len(upstream.stats.ErrorsByKind))
+	for kind, n := range upstream.stats.ErrorsByKind {
+		stats.ErrorsByKind[kind] = n
+	}
+	return stats
+}
@@ -0,0 +1,115 @@
+package fotmob
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/cache"
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+)
+
+// Where DetectVenue got a match's venue from, most trusted first
+const (
+	VenueFromOverride     = "override"
+	VenueFromFixture      = "fixture"
+	VenueFromMatchDetails = "match-details"
+	VenueFromHomeTeam     = "home-team"
+	VenueUnknown          = "unknown"
+)
+
+// VenueTTL is how long a venue read from match details is considered
+// current; older ones are still used until they are looked up again
+const VenueTTL = 12 * time.Hour
+
+// venueCache holds the venue from each match's details, "" when the
+// details named none
+var venueCache = cache.New()
+
+// rememberVenue records the venue given in a match's details
+func rememberVenue(details *MatchDetails) {
+	venue := ""
+	if details.Venue != nil {
+		venue = details.Venue.Name
+	}
+	venueCache.Set(strconv.Itoa(details.ID), venue, VenueTTL)
+}
+
+// DetectVenue decides where a raw league match is played and says on what
+// basis: an admin override, a venue in the provider's fixture, the match
+// details, or failing those the home club's ground. Known venues are
+// returned under their canonical names.
+func DetectVenue(matchMap map[string]interface{}) (venue, source string) {
+	if venue, ok := venueOverride(matchMap); ok {
+		return teams.CanonicalVenue(venue), VenueFromOverride
+	}
+	if venue, ok := matchMap["venue"].(string); ok && venue != "" {
+		return teams.CanonicalVenue(venue), VenueFromFixture
+	}
+	if entry, ok := venueCache.GetEntry(idString(matchMap["id"])); ok {
+		if venue, _ := entry.Value.(string); venue != "" {
+			return teams.CanonicalVenue(venue), VenueFromMatchDetails
+		}
+	}
+	if home, ok := matchMap["home"].(map[string]interface{}); ok {
+		name, _ := home["name"].(string)
+		if team, ok := teams.Default.Resolve(teamID(home), name); ok && team.HomeVenue != "" {
+			return team.HomeVenue, VenueFromHomeTeam
+		}
+	}
+	return "", VenueUnknown
+}
+
+// venueOverride returns the venue of a match whose venue was overridden
+func venueOverride(matchMap map[string]interface{}) (string, bool) {
+	fields, _ := matchMap["overridden"].([]string)
+	for _, field := range fields {
+		if field == "venue" {
+			venue, ok := matchMap["venue"].(string)
+			return venue, ok
+		}
+	}
+	return "", false
+}
+
+// VenueCandidates returns the IDs of Fotmob fixtures kicking off within
+// lookahead whose venue only the home-team guess gives and whose details
+// haven't been looked at recently, soonest first
+func VenueCandidates(fixtures []interface{}, lookahead time.Duration) []int {
+	now := time.Now()
+	type candidate struct {
+		id      int
+		kickoff time.Time
+	}
+	var candidates []candidate
+	for _, fixture := range fixtures {
+		matchMap, ok := fixture.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if source, ok := matchMap["source"].(string); ok && source != "fotmob" {
+			continue
+		}
+		if _, source := DetectVenue(matchMap); source == VenueFromOverride || source == VenueFromFixture {
+			continue
+		}
+		kickoff, ok := matchKickoff(matchMap)
+		if !ok || kickoff.Before(now) || kickoff.After(now.Add(lookahead)) {
+			continue
+		}
+		key := idString(matchMap["id"])
+		if _, fresh := venueCache.Get(key); fresh {
+			continue
+		}
+		if id, err := strconv.Atoi(key); err == nil && id > 0 {
+			candidates = append(candidates, candidate{id, kickoff})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].kickoff.Before(candidates[j].kickoff) })
+	ids := make([]int, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.id
+	}
+	return ids
+}
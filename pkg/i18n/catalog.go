@@ -0,0 +1,129 @@
+package i18n
+
+// Translation catalogs keyed by locale, then message key
+var catalogs = map[Locale]map[string]string{
+	English: {
+		"stadium.name": "Sammy Ofer Stadium",
+
+		"status.scheduled": "Scheduled",
+		"status.live":      "Live",
+		"status.halftime":  "Half-time",
+		"status.finished":  "Full-time",
+		"status.cancelled": "Cancelled",
+		"status.postponed": "Postponed",
+		"status.abandoned": "Abandoned",
+		"status.tbc":       "Time to be confirmed",
+
+		"page.schedule":       "Schedule",
+		"page.schedule.title": "Upcoming matches at Sammy Ofer Stadium",
+		"page.schedule.empty": "No upcoming matches are scheduled.",
+		"page.stadium":        "Stadium info",
+		"page.details":        "Match details",
+		"page.back":           "All matches",
+		"page.otherLanguage":  "עברית",
+		"page.stale":          "Showing saved data from %s; live data is temporarily unavailable.",
+		"page.round":          "Round",
+		"page.venue":          "Venue",
+		"page.referee":        "Referee",
+		"page.attendance":     "Attendance",
+		"page.goals":          "Goals",
+		"page.ownGoal":        "own goal",
+		"page.penalty":        "penalty",
+		"page.cards":          "Cards",
+		"page.lineups":        "Lineups",
+		"page.coach":          "Coach",
+		"page.bench":          "Bench",
+		"page.stats":          "Statistics",
+		"page.h2h":            "Head to head",
+		"page.played":         "Played",
+		"page.wins":           "Wins",
+		"page.draws":          "Draws",
+		"page.recent":         "Recent meetings",
+		"page.capacity":       "Capacity",
+		"page.address":        "Address",
+		"page.clubs":          "Home clubs",
+
+		"busy.calendar":       "%s busy windows",
+		"busy.summary":        "Stadium busy: %s – %s",
+		"busy.gatesOpen":      "Gates open",
+		"busy.kickoff":        "Kickoff",
+		"busy.clearance":      "Area clear by",
+		"busy.crowd":          "Expected crowd: %d (%s)",
+		"busy.level.low":      "light traffic",
+		"busy.level.moderate": "moderate traffic",
+		"busy.level.high":     "heavy traffic",
+	},
+	Hebrew: {
+		"stadium.name": "אצטדיון סמי עופר",
+
+		"status.scheduled": "מתוכנן",
+		"status.live":      "בשידור חי",
+		"status.halftime":  "מחצית",
+		"status.finished":  "הסתיים",
+		"status.cancelled": "בוטל",
+		"status.postponed": "נדחה",
+		"status.abandoned": "הופסק",
+		"status.tbc":       "השעה טרם נקבעה",
+
+		"page.schedule":       "לוח משחקים",
+		"page.schedule.title": "המשחקים הקרובים באצטדיון סמי עופר",
+		"page.schedule.empty": "אין משחקים מתוכננים כרגע.",
+		"page.stadium":        "מידע על האצטדיון",
+		"page.details":        "פרטי המשחק",
+		"page.back":           "כל המשחקים",
+		"page.otherLanguage":  "English",
+		"page.stale":          "מוצגים נתונים שמורים מ-%s; הנתונים העדכניים אינם זמינים כרגע.",
+		"page.round":          "מחזור",
+		"page.venue":          "אצטדיון",
+		"page.referee":        "שופט",
+		"page.attendance":     "קהל",
+		"page.goals":          "שערים",
+		"page.ownGoal":        "שער עצמי",
+		"page.penalty":        "פנדל",
+		"page.cards":          "כרטיסים",
+		"page.lineups":        "הרכבים",
+		"page.coach":          "מאמן",
+		"page.bench":          "ספסל",
+		"page.stats":          "סטטיסטיקה",
+		"page.h2h":            "ראש בראש",
+		"page.played":         "משחקים",
+		"page.wins":           "ניצחונות",
+		"page.draws":          "תיקו",
+		"page.recent":         "מפגשים אחרונים",
+		"page.capacity":       "קיבולת",
+		"page.address":        "כתובת",
+		"page.clubs":          "קבוצות בית",
+
+		"busy.calendar":       "%s - שעות עומס",
+		"busy.summary":        "עומס באצטדיון: %s – %s",
+		"busy.gatesOpen":      "פתיחת שערים",
+		"busy.kickoff":        "שריקת פתיחה",
+		"busy.clearance":      "פינוי האזור עד",
+		"busy.crowd":          "קהל צפוי: %d (%s)",
+		"busy.level.low":      "עומס קל",
+		"busy.level.moderate": "עומס בינוני",
+		"busy.level.high":     "עומס כבד",
+	},
+}
+
+// Competition names as Fotmob reports them, translated per locale
+var competitionNames = map[Locale]map[string]string{
+	Hebrew: {
+		"Ligat Ha'Al":                     "ליגת העל",
+		"Ligat HaAl":                      "ליגת העל",
+		"Liga Leumit":                     "הליגה הלאומית",
+		"State Cup":                       "גביע המדינה",
+		"Israel State Cup":                "גביע המדינה",
+		"Toto Cup":                        "גביע הטוטו",
+		"Toto Cup Al":                     "גביע הטוטו",
+		"Super Cup":                       "אלוף האלופים",
+		"Israel Super Cup":                "אלוף האלופים",
+		"Champions League":                "ליגת האלופות",
+		"Europa League":                   "הליגה האירופית",
+		"Conference League":               "הקונפרנס ליג",
+		"UEFA Conference League":          "הקונפרנס ליג",
+		"Champions League Qualification":  "מוקדמות ליגת האלופות",
+		"Europa League Qualification":     "מוקדמות הליגה האירופית",
+		"Conference League Qualification": "מוקדמות הקונפרנס ליג",
+	},
+}
@@ -0,0 +1,47 @@
+package i18n
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	// Embed the timezone database so Asia/Jerusalem resolves in slim containers
+	_ "time/tzdata"
+)
+
+// Jerusalem is the timezone all user-facing dates are rendered in
+var Jerusalem = loadJerusalem()
+
+func loadJerusalem() *time.Location {
+	loc, err := time.LoadLocation("Asia/Jerusalem")
+	if err != nil {
+		log.Printf("Failed to load Asia/Jerusalem timezone, falling back to UTC: %v", err)
+		return time.UTC
+	}
+	return loc
+}
+
+var hebrewWeekdays = [...]string{
+	"יום ראשון", "יום שני", "יום שלישי", "יום רביעי", "יום חמישי", "יום שישי", "שבת",
+}
+
+var hebrewMonths = [...]string{
+	"ינואר", "פברואר", "מרץ", "אפריל", "מאי", "יוני",
+	"יולי", "אוגוסט", "ספטמבר", "אוקטובר", "נובמבר", "דצמבר",
+}
+
+// FormatDate renders the calendar date of t in Jerusalem time, e.g.
+// "Sat, 24 Aug 2024" or "שבת, 24 באוגוסט 2024"
+func FormatDate(locale Locale, t time.Time) string {
+	local := t.In(Jerusalem)
+	if locale == Hebrew {
+		return fmt.Sprintf("%s, %d ב%s %d",
+			hebrewWeekdays[local.Weekday()], local.Day(), hebrewMonths[local.Month()-1], local.Year())
+	}
+	return local.Format("Mon, 2 Jan 2006")
+}
+
+// FormatTime renders the wall-clock time of t in Jerusalem time (24-hour)
+func FormatTime(locale Locale, t time.Time) string {
+	return t.In(Jerusalem).Format("15:04")
+}
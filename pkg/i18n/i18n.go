@@ -0,0 +1,123 @@
+package i18n
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+)
+
+// Locale is a supported response language
+type Locale string
+
+const (
+	English Locale = "en"
+	Hebrew  Locale = "he"
+)
+
+// DefaultLocale is used when the client doesn't ask for a supported language
+const DefaultLocale = English
+
+// Parse maps a language tag such as "he-IL" or "iw" to a supported locale
+func Parse(tag string) (Locale, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+
+	switch tag {
+	case "he", "iw":
+		return Hebrew, true
+	case "en":
+		return English, true
+	}
+	return "", false
+}
+
+// FromRequest negotiates the response locale. An explicit ?lang= parameter
+// wins, then the highest-weighted supported Accept-Language entry.
+func FromRequest(r *http.Request) Locale {
+	if locale, ok := Parse(r.URL.Query().Get("lang")); ok {
+		return locale
+	}
+
+	type candidate struct {
+		locale Locale
+		q      float64
+	}
+	candidates := []candidate{}
+
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		fields := strings.Split(part, ";")
+		locale, ok := Parse(fields[0])
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if parsed, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{locale, q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLocale
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].locale
+}
+
+// SetHeaders marks a response as localized so caches key on the language
+func SetHeaders(w http.ResponseWriter, locale Locale) {
+	w.Header().Set("Content-Language", string(locale))
+	w.Header().Add("Vary", "Accept-Language")
+}
+
+// T looks up a catalog key, falling back to English and then to the key itself
+func T(locale Locale, key string) string {
+	if msg, ok := catalogs[locale][key]; ok {
+		return msg
+	}
+	if msg, ok := catalogs[English][key]; ok {
+		return msg
+	}
+	return key
+}
+
+// Competition translates a Fotmob competition name, returning it unchanged
+// when there's no translation
+func Competition(locale Locale, name string) string {
+	if locale == English {
+		return name
+	}
+	if translated, ok := competitionNames[locale][name]; ok {
+		return translated
+	}
+	return name
+}
+
+// TeamName returns a team's display name in the given locale
+func TeamName(locale Locale, team teams.Team) string {
+	if locale == Hebrew && team.NameHe != "" {
+		return team.NameHe
+	}
+	return team.Name
+}
+
+// Status translates a match status label (see the status.* catalog keys)
+func Status(locale Locale, status string) string {
+	return T(locale, "status."+status)
+}
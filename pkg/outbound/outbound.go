@@ -0,0 +1,343 @@
+// Package outbound limits the requests we send to fotmob.com. Every caller
+// in the process shares one token bucket and one daily budget, so page
+// loads, token refreshes and scraper fallbacks together stay polite.
+package outbound
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExhausted is returned once the day's request budget is used up.
+// Background work hits it earlier, at its share of the budget.
+var ErrBudgetExhausted = errors.New("daily fotmob.com request budget exhausted")
+
+// Priority orders callers competing for the bucket
+type Priority int
+
+const (
+	// User is a fetch someone is waiting on, the default
+	User Priority = iota
+	// Background is polling and refreshes nobody is waiting on
+	Background
+)
+
+func (p Priority) String() string {
+	if p == Background {
+		return "background"
+	}
+	return "user"
+}
+
+type priorityKey struct{}
+
+// WithPriority marks requests made with ctx as having priority p
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFrom returns the priority set by WithPriority, User if none
+func PriorityFrom(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	return User
+}
+
+// Config sets the limits: Burst requests at once, then one per Every, and at
+// most DailyBudget per UTC day (0 for no daily limit). Background requests
+// stop once the day's total reaches BackgroundShare of the budget, and never
+// take the last quarter of the bucket.
+type Config struct {
+	Burst           int
+	Every           time.Duration
+	DailyBudget     int
+	BackgroundShare float64
+}
+
+// DefaultConfig is used until Configure is called
+var DefaultConfig = Config{
+	Burst:           10,
+	Every:           2 * time.Second,
+	DailyBudget:     5000,
+	BackgroundShare: 0.5,
+}
+
+// Usage reports the day's requests against the budget
+type Usage struct {
+	Day              string         `json:"day"`
+	Budget           int            `json:"budget"`
+	BackgroundBudget int            `json:"backgroundBudget"`
+	Used             int            `json:"used"`
+	Remaining        int            `json:"remaining"`
+	ByPriority       map[string]int `json:"byPriority"`
+	ByCaller         map[string]int `json:"byCaller"`
+	// Throttled counts requests that had to wait for the bucket
+	Throttled int `json:"throttled"`
+	// Refused counts requests turned away because the budget ran out
+	Refused  int       `json:"refused"`
+	ResetsAt time.Time `json:"resetsAt"`
+}
+
+// Limiter is a token bucket with a daily budget and two priorities
+type Limiter struct {
+	mu     sync.Mutex
+	config Config
+
+	capacity float64
+	rate     float64 // tokens per second
+	reserve  float64 // tokens background requests leave for users
+	tokens   float64
+	last     time.Time
+
+	day          string
+	used         int
+	byPriority   map[string]int
+	byCaller     map[string]int
+	throttled    int
+	refused      int
+	warnedAt     int // highest budget percentage already logged
+	usersWaiting int
+
+	now func() time.Time
+}
+
+// NewLimiter creates a limiter; zero Config fields take DefaultConfig values
+func NewLimiter(config Config) *Limiter {
+	l := &Limiter{now: time.Now}
+	l.configure(config)
+	return l
+}
+
+func (l *Limiter) configure(config Config) {
+	if config.Burst <= 0 {
+		config.Burst = DefaultConfig.Burst
+	}
+	if config.Every <= 0 {
+		config.Every = DefaultConfig.Every
+	}
+	if config.DailyBudget < 0 {
+		config.DailyBudget = DefaultConfig.DailyBudget
+	}
+	if config.BackgroundShare <= 0 || config.BackgroundShare > 1 {
+		config.BackgroundShare = DefaultConfig.BackgroundShare
+	}
+
+	l.config = config
+	l.capacity = float64(config.Burst)
+	l.rate = 1 / config.Every.Seconds()
+	l.reserve = math.Ceil(l.capacity / 4)
+	if l.reserve >= l.capacity {
+		l.reserve = l.capacity - 1
+	}
+	l.tokens = l.capacity
+	l.last = l.now()
+	if l.byPriority == nil {
+		l.rollover(l.last)
+	}
+}
+
+var defaultLimiter = NewLimiter(DefaultConfig)
+
+// Configure replaces the process-wide limits. Today's usage is kept.
+func Configure(config Config) {
+	defaultLimiter.mu.Lock()
+	defer defaultLimiter.mu.Unlock()
+	defaultLimiter.configure(config)
+	log.Printf("Outbound limit: burst %d, one per %v, daily budget %d (background %d%%)",
+		defaultLimiter.config.Burst, defaultLimiter.config.Every,
+		defaultLimiter.config.DailyBudget, int(defaultLimiter.config.BackgroundShare*100))
+}
+
+// Wait blocks until the process-wide limiter lets a request to fotmob.com
+// go out. caller names the kind of request in the usage report.
+func Wait(ctx context.Context, caller string) error {
+	return defaultLimiter.Wait(ctx, caller)
+}
+
+// CurrentUsage reports the process-wide limiter's usage
+func CurrentUsage() Usage {
+	return defaultLimiter.Usage()
+}
+
+// ResetIn is how long until the process-wide daily budget resets
+func ResetIn() time.Duration {
+	return defaultLimiter.ResetIn()
+}
+
+// Wait blocks until a request may go out, the budget is exhausted, or ctx
+// ends. The priority comes from ctx; while user requests are waiting,
+// background ones don't get a token.
+func (l *Limiter) Wait(ctx context.Context, caller string) error {
+	priority := PriorityFrom(ctx)
+	waiting := false
+	defer func() {
+		if waiting && priority == User {
+			l.mu.Lock()
+			l.usersWaiting--
+			l.mu.Unlock()
+		}
+	}()
+
+	for {
+		l.mu.Lock()
+		now := l.now()
+		if l.day != day(now) {
+			l.rollover(now)
+		}
+
+		if l.config.DailyBudget > 0 && l.used >= l.budgetFor(priority) {
+			l.refused++
+			l.mu.Unlock()
+			return ErrBudgetExhausted
+		}
+
+		l.refill(now)
+		need := 1.0
+		if priority == Background {
+			need += l.reserve
+			if l.usersWaiting > 0 {
+				need = math.Inf(1)
+			}
+		}
+		if l.tokens >= need {
+			l.tokens--
+			l.record(priority, caller, waiting)
+			l.mu.Unlock()
+			return nil
+		}
+
+		wait := l.config.Every
+		if !math.IsInf(need, 1) {
+			wait = time.Duration(math.Ceil((need - l.tokens) / l.rate * float64(time.Second)))
+		}
+		if !waiting && priority == User {
+			l.usersWaiting++
+		}
+		waiting = true
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// budgetFor returns how many requests the day may have had before priority
+// is refused
+func (l *Limiter) budgetFor(priority Priority) int {
+	if priority == Background {
+		return l.backgroundBudget()
+	}
+	return l.config.DailyBudget
+}
+
+func (l *Limiter) backgroundBudget() int {
+	return int(float64(l.config.DailyBudget) * l.config.BackgroundShare)
+}
+
+func (l *Limiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last).Seconds(); elapsed > 0 {
+		l.tokens = math.Min(l.capacity, l.tokens+elapsed*l.rate)
+	}
+	l.last = now
+}
+
+// record counts a request and logs when it crosses 50, 80 or 100 percent
+// of the daily budget
+func (l *Limiter) record(priority Priority, caller string, waited bool) {
+	l.used++
+	l.byPriority[priority.String()]++
+	l.byCaller[caller]++
+	if waited {
+		l.throttled++
+	}
+
+	if l.config.DailyBudget == 0 {
+		return
+	}
+	percent := l.used * 100 / l.config.DailyBudget
+	for _, threshold := range []int{100, 80, 50} {
+		if percent >= threshold {
+			if l.warnedAt < threshold {
+				l.warnedAt = threshold
+				log.Printf("Outbound budget: %d of %d fotmob.com requests used today (%d%%)", l.used, l.config.DailyBudget, threshold)
+			}
+			break
+		}
+	}
+}
+
+// rollover starts a new day's counters
+func (l *Limiter) rollover(now time.Time) {
+	if l.day != "" {
+		log.Printf("Outbound budget for %s: %d of %d requests used, %d throttled, %d refused",
+			l.day, l.used, l.config.DailyBudget, l.throttled, l.refused)
+	}
+	l.day = day(now)
+	l.used = 0
+	l.byPriority = make(map[string]int)
+	l.byCaller = make(map[string]int)
+	l.throttled = 0
+	l.refused = 0
+	l.warnedAt = 0
+}
+
+// Usage returns a snapshot of the day's usage
+func (l *Limiter) Usage() Usage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if l.day != day(now) {
+		l.rollover(now)
+	}
+
+	usage := Usage{
+		Day:              l.day,
+		Budget:           l.config.DailyBudget,
+		BackgroundBudget: l.backgroundBudget(),
+		Used:             l.used,
+		ByPriority:       make(map[string]int, len(l.byPriority)),
+		ByCaller:         make(map[string]int, len(l.byCaller)),
+		Throttled:        l.throttled,
+		Refused:          l.refused,
+		ResetsAt:         nextReset(now),
+	}
+	if l.config.DailyBudget > 0 {
+		usage.Remaining = l.config.DailyBudget - l.used
+		if usage.Remaining < 0 {
+			usage.Remaining = 0
+		}
+	}
+	for k, v := range l.byPriority {
+		usage.ByPriority[k] = v
+	}
+	for k, v := range l.byCaller {
+		usage.ByCaller[k] = v
+	}
+	return usage
+}
+
+// ResetIn is how long until the daily budget resets
+func (l *Limiter) ResetIn() time.Duration {
+	now := l.now()
+	return nextReset(now).Sub(now)
+}
+
+func day(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+func nextReset(now time.Time) time.Time {
+	y, m, d := now.UTC().Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,320 @@
+package overrides
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/fotmob"
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+)
+
+// Apply merges the overrides into raw fixtures in Fotmob's league match
+// shape. Corrected matches are copies, since providers hand out cached maps,
+// and carry an "overridden" list of the corrected fields. Manual fixtures no
+// provider lists are appended. When fresh is set the fixtures just came from
+// a provider, and overrides they now agree with expire.
+func (s *Store) Apply(fixtures []interface{}, fresh bool) []interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.overrides) == 0 {
+		return fixtures
+	}
+
+	merged := make([]interface{}, 0, len(fixtures)+len(s.overrides))
+	seen := make(map[int]bool)
+	var expired []Override
+	for _, fixture := range fixtures {
+		matchMap, ok := fixture.(map[string]interface{})
+		if !ok {
+			merged = append(merged, fixture)
+			continue
+		}
+		id := fixtureID(matchMap)
+		o, ok := s.overrides[id]
+		if !ok {
+			merged = append(merged, fixture)
+			continue
+		}
+		seen[id] = true
+
+		if fresh && o.agrees(matchMap) {
+			expired = append(expired, o)
+			merged = append(merged, fixture)
+			continue
+		}
+		merged = append(merged, o.apply(matchMap))
+	}
+
+	for _, o := range s.overrides {
+		if !o.Manual || seen[o.MatchID] {
+			continue
+		}
+		if listed, ok := findListed(fixtures, o); ok {
+			// Upstream has the game under its own ID
+			if fresh {
+				log.Printf("Manual fixture %d is now listed upstream as match %d", o.MatchID, listed)
+				expired = append(expired, o)
+			}
+			continue
+		}
+		merged = append(merged, o.matchMap())
+	}
+
+	for _, o := range expired {
+		before := o
+		err := s.change(time.Now().UTC(), func() {
+			delete(s.overrides, o.MatchID)
+		}, AuditEntry{Actor: "upstream", Action: ActionExpire, MatchID: o.MatchID, Before: &before, Reason: "upstream agrees"})
+		if err != nil {
+			log.Printf("Warning: Failed to expire override for match %d: %v", o.MatchID, err)
+		}
+	}
+	return merged
+}
+
+// Details returns the details of a manual fixture no provider knows about
+func (s *Store) Details(matchID int) (*fotmob.MatchDetails, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.overrides[matchID]
+	if !ok || !o.Manual {
+		return nil, false
+	}
+	return o.details(), true
+}
+
+// ApplyDetails returns a copy of details with the match's override applied,
+// or details itself when it has none
+func (s *Store) ApplyDetails(details *fotmob.MatchDetails) *fotmob.MatchDetails {
+	s.mu.Lock()
+	o, ok := s.overrides[details.ID]
+	s.mu.Unlock()
+	if !ok {
+		return details
+	}
+
+	corrected := *details
+	if o.Kickoff != nil {
+		corrected.UTCTime = o.Kickoff.Format(time.RFC3339)
+	}
+	if o.Venue != nil {
+		venue := fotmob.MatchVenue{Name: *o.Venue}
+		if details.Venue != nil && teams.SameVenue(details.Venue.Name, *o.Venue) {
+			venue = *details.Venue
+		}
+		corrected.Venue = &venue
+	}
+	if o.Status != nil {
+		corrected.Status = o.matchStatus(details.Status)
+	}
+	corrected.Overridden = o.Fields()
+	return &corrected
+}
+
+// agrees reports whether an upstream match now says what the override does.
+// A manual fixture agrees as soon as upstream lists it.
+func (o Override) agrees(matchMap map[string]interface{}) bool {
+	if o.Manual {
+		return true
+	}
+
+	upstream := fotmob.NewMatch(matchMap, i18n.DefaultLocale)
+	if o.Kickoff != nil && (upstream.Kickoff == nil || !upstream.Kickoff.Equal(*o.Kickoff)) {
+		return false
+	}
+	// A corrected kickoff is a confirmed one unless timeTbc says otherwise
+	if o.Kickoff != nil && o.TimeTBC == nil && upstream.TimeTBC {
+		return false
+	}
+	if o.TimeTBC != nil && upstream.TimeTBC != *o.TimeTBC {
+		return false
+	}
+	if o.Status != nil && upstream.Status != *o.Status {
+		return false
+	}
+	// The venue is upstream's best guess, down to the home club's ground
+	if o.Venue != nil && !teams.SameVenue(upstream.Venue, *o.Venue) {
+		return false
+	}
+	return true
+}
+
+func sameTeam(a, b string) bool {
+	teamA, okA := teams.Default.Resolve(0, a)
+	teamB, okB := teams.Default.Resolve(0, b)
+	if okA && okB {
+		return teamA.Name == teamB.Name
+	}
+	return teams.Normalize(a) == teams.Normalize(b)
+}
+
+// findListed looks for a manual fixture among upstream fixtures by teams and
+// Israeli calendar date, returning the upstream match ID
+func findListed(fixtures []interface{}, o Override) (int, bool) {
+	day := o.Kickoff.In(i18n.Jerusalem).Format("2006-01-02")
+	for _, fixture := range fixtures {
+		matchMap, ok := fixture.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		match := fotmob.NewMatch(matchMap, i18n.DefaultLocale)
+		if match.Kickoff == nil || match.Kickoff.In(i18n.Jerusalem).Format("2006-01-02") != day {
+			continue
+		}
+		if sameTeam(match.HomeTeam, o.HomeTeam) && sameTeam(match.AwayTeam, o.AwayTeam) {
+			return match.ID, true
+		}
+	}
+	return 0, false
+}
+
+func fixtureID(matchMap map[string]interface{}) int {
+	switch v := matchMap["id"].(type) {
+	case string:
+		id, _ := strconv.Atoi(v)
+		return id
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// apply returns a copy of matchMap with the override's fields set
+func (o Override) apply(matchMap map[string]interface{}) map[string]interface{} {
+	corrected := make(map[string]interface{}, len(matchMap)+2)
+	for k, v := range matchMap {
+		corrected[k] = v
+	}
+	status := make(map[string]interface{})
+	if upstream, ok := matchMap["status"].(map[string]interface{}); ok {
+		for k, v := range upstream {
+			status[k] = v
+		}
+	}
+	corrected["status"] = status
+
+	if o.Kickoff != nil {
+		status["utcTime"] = o.Kickoff.Format(time.RFC3339)
+		delete(corrected, "timeTS")
+		// A corrected kickoff is a confirmed one unless said otherwise
+		if o.TimeTBC == nil {
+			setTimeTBC(corrected, status, false)
+		}
+	}
+	if o.TimeTBC != nil {
+		setTimeTBC(corrected, status, *o.TimeTBC)
+	}
+	if o.Venue != nil {
+		corrected["venue"] = *o.Venue
+	}
+	if o.Status != nil {
+		setStatus(status, *o.Status)
+	}
+	corrected["overridden"] = o.Fields()
+	return corrected
+}
+
+func setTimeTBC(matchMap, status map[string]interface{}, tbc bool) {
+	matchMap["timeTBD"] = tbc
+	status["timeTBD"] = tbc
+	if !tbc {
+		if reason, ok := status["reason"].(map[string]interface{}); ok {
+			short, _ := reason["short"].(string)
+			switch strings.ToUpper(short) {
+			case "TBC", "TBD", "TBA":
+				delete(status, "reason")
+			}
+		}
+	}
+}
+
+// setStatus sets Fotmob's status flags and reason for one of Statuses
+func setStatus(status map[string]interface{}, value string) {
+	flags := statusFlags(value)
+	status["started"] = flags.Started
+	status["finished"] = flags.Finished
+	status["cancelled"] = flags.Cancelled
+	delete(status, "reason")
+	if flags.Short != "" {
+		status["reason"] = map[string]interface{}{"short": flags.Short}
+	}
+}
+
+func statusFlags(value string) fotmob.MatchStatus {
+	switch value {
+	case "postponed":
+		return fotmob.MatchStatus{Short: "PP"}
+	case "cancelled":
+		return fotmob.MatchStatus{Cancelled: true}
+	case "abandoned":
+		return fotmob.MatchStatus{Started: true, Short: "AB"}
+	}
+	return fotmob.MatchStatus{}
+}
+
+// matchStatus is upstream's status with the override's flags; the score is
+// kept for an abandoned match
+func (o Override) matchStatus(upstream fotmob.MatchStatus) fotmob.MatchStatus {
+	status := statusFlags(*o.Status)
+	if *o.Status == "abandoned" {
+		status.Score = upstream.Score
+	}
+	return status
+}
+
+// matchMap renders a manual fixture in Fotmob's league match shape
+func (o Override) matchMap() map[string]interface{} {
+	match := map[string]interface{}{
+		"id":     strconv.Itoa(o.MatchID),
+		"home":   map[string]interface{}{"name": o.HomeTeam},
+		"away":   map[string]interface{}{"name": o.AwayTeam},
+		"status": map[string]interface{}{"utcTime": o.Kickoff.Format(time.RFC3339), "started": false, "finished": false, "cancelled": false},
+		"source": "manual",
+	}
+	if o.Round != "" {
+		match["round"] = o.Round
+	}
+	return o.apply(match)
+}
+
+// details builds match details for a manual fixture
+func (o Override) details() *fotmob.MatchDetails {
+	details := &fotmob.MatchDetails{
+		ID:            o.MatchID,
+		Competition:   "Ligat Ha'Al",
+		Round:         o.Round,
+		UTCTime:       o.Kickoff.Format(time.RFC3339),
+		HomeTeam:      manualTeam(o.HomeTeam),
+		AwayTeam:      manualTeam(o.AwayTeam),
+		Goals:         []fotmob.GoalEvent{},
+		Cards:         []fotmob.CardEvent{},
+		Substitutions: []fotmob.SubstitutionEvent{},
+		Stats:         []fotmob.StatGroup{},
+		Source:        "manual",
+		Overridden:    o.Fields(),
+	}
+	if o.Venue != nil {
+		details.Venue = &fotmob.MatchVenue{Name: *o.Venue}
+	}
+	if o.Status != nil {
+		details.Status = statusFlags(*o.Status)
+	}
+	return details
+}
+
+func manualTeam(name string) fotmob.MatchTeam {
+	team := fotmob.MatchTeam{Name: name}
+	if club, ok := teams.Default.Resolve(0, name); ok {
+		if club.ID != 0 {
+			team.ID = club.ID
+			team.LogoURL = teams.LogoURL(club.ID)
+		}
+		team.Club = &club
+	}
+	return team
+}
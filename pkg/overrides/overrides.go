@@ -0,0 +1,369 @@
+// Package overrides holds admin corrections to fixtures: a wrong kickoff, a
+// relocated or called-off game, or a fixture no provider lists at all. They
+// are kept in a JSON file with an audit trail, merged over provider data by
+// match ID before the Sammy Ofer filter, and expire once upstream agrees.
+package overrides
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+)
+
+var (
+	// ErrNotFound is returned for a match without an override
+	ErrNotFound = errors.New("override not found")
+	// ErrExists is returned when creating a second override for a match
+	ErrExists = errors.New("an override for this match already exists")
+	// ErrInvalid wraps validation failures
+	ErrInvalid = errors.New("invalid override")
+)
+
+// Statuses an override may set, as in Match.Status
+var Statuses = []string{"scheduled", "postponed", "cancelled", "abandoned"}
+
+// manualIDBase keeps IDs given to manual fixtures clear of Fotmob's match
+// IDs and of those derived for feed rows
+const manualIDBase = 2_000_000_000
+
+// Override corrects one fixture. Nil fields leave the provider's value alone.
+// A manual override is a whole fixture of its own, shown even when no
+// provider lists it.
+type Override struct {
+	MatchID int  `json:"matchId"`
+	Manual  bool `json:"manual,omitempty"`
+	// HomeTeam, AwayTeam and Round describe a manual fixture
+	HomeTeam string `json:"homeTeam,omitempty"`
+	AwayTeam string `json:"awayTeam,omitempty"`
+	Round    string `json:"round,omitempty"`
+
+	Kickoff *time.Time `json:"kickoff,omitempty"`
+	TimeTBC *bool      `json:"timeTbc,omitempty"`
+	Venue   *string    `json:"venue,omitempty"`
+	Status  *string    `json:"status,omitempty"`
+
+	// Note explains the correction to other admins
+	Note string `json:"note,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	CreatedBy string    `json:"createdBy"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	UpdatedBy string    `json:"updatedBy"`
+}
+
+// Fields lists what the override corrects, starting with "manual" for a
+// manual fixture
+func (o Override) Fields() []string {
+	fields := []string{}
+	if o.Manual {
+		fields = append(fields, "manual")
+	}
+	if o.Kickoff != nil {
+		fields = append(fields, "kickoff")
+	}
+	if o.TimeTBC != nil {
+		fields = append(fields, "timeTbc")
+	}
+	if o.Venue != nil {
+		fields = append(fields, "venue")
+	}
+	if o.Status != nil {
+		fields = append(fields, "status")
+	}
+	return fields
+}
+
+func (o *Override) normalize() {
+	o.HomeTeam = strings.TrimSpace(o.HomeTeam)
+	o.AwayTeam = strings.TrimSpace(o.AwayTeam)
+	o.Round = strings.TrimSpace(o.Round)
+	o.Note = strings.TrimSpace(o.Note)
+	if o.Kickoff != nil {
+		kickoff := o.Kickoff.UTC().Truncate(time.Minute)
+		o.Kickoff = &kickoff
+	}
+	if o.Venue != nil {
+		venue := strings.TrimSpace(*o.Venue)
+		o.Venue = &venue
+	}
+	if o.Status != nil {
+		status := strings.ToLower(strings.TrimSpace(*o.Status))
+		o.Status = &status
+	}
+	if o.Manual && o.MatchID == 0 && o.Kickoff != nil {
+		o.MatchID = manualID(*o)
+	}
+}
+
+func (o Override) validate() error {
+	if o.MatchID <= 0 {
+		return fmt.Errorf("%w: matchId is required", ErrInvalid)
+	}
+	if o.Manual {
+		if o.HomeTeam == "" || o.AwayTeam == "" || o.Kickoff == nil {
+			return fmt.Errorf("%w: a manual fixture needs homeTeam, awayTeam and kickoff", ErrInvalid)
+		}
+	} else if len(o.Fields()) == 0 {
+		return fmt.Errorf("%w: set at least one of kickoff, timeTbc, venue and status", ErrInvalid)
+	}
+	if o.Venue != nil && *o.Venue == "" {
+		return fmt.Errorf("%w: venue may not be empty", ErrInvalid)
+	}
+	if o.Status != nil {
+		known := false
+		for _, status := range Statuses {
+			known = known || *o.Status == status
+		}
+		if !known {
+			return fmt.Errorf("%w: status must be one of %s", ErrInvalid, strings.Join(Statuses, ", "))
+		}
+	}
+	return nil
+}
+
+// manualID gives a manual fixture without a match ID a stable one from its
+// date and teams
+func manualID(o Override) int {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s|%s|%s", o.Kickoff.In(i18n.Jerusalem).Format("2006-01-02"), teams.Normalize(o.HomeTeam), teams.Normalize(o.AwayTeam))
+	return manualIDBase + int(h.Sum32()%100_000_000)
+}
+
+// Audit actions
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+	ActionExpire = "expire"
+)
+
+// AuditEntry records one change to the overrides. Expiries are made by
+// "upstream".
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	Actor   string    `json:"actor"`
+	Action  string    `json:"action"`
+	MatchID int       `json:"matchId"`
+	Before  *Override `json:"before,omitempty"`
+	After   *Override `json:"after,omitempty"`
+	Reason  string    `json:"reason,omitempty"`
+}
+
+// Store keeps the overrides and their audit trail in a JSON file
+type Store struct {
+	path string
+
+	mu        sync.Mutex
+	overrides map[int]Override
+	audit     []AuditEntry
+	updatedAt time.Time
+	// loadErr makes the store refuse writes that would replace a file it
+	// couldn't read
+	loadErr error
+}
+
+type storeFile struct {
+	Overrides []Override   `json:"overrides"`
+	Audit     []AuditEntry `json:"audit"`
+}
+
+// Open loads the store at path; a missing file is an empty store. If the file
+// can't be read the store is returned empty along with the error, and refuses
+// changes until the file is fixed.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, overrides: make(map[int]Override), audit: []AuditEntry{}}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		s.loadErr = err
+		return s, err
+	}
+
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		s.loadErr = fmt.Errorf("overrides file %s is corrupt: %v", path, err)
+		return s, s.loadErr
+	}
+	for _, o := range file.Overrides {
+		s.overrides[o.MatchID] = o
+	}
+	if file.Audit != nil {
+		s.audit = file.Audit
+	}
+	if info, err := os.Stat(path); err == nil {
+		s.updatedAt = info.ModTime()
+	}
+	log.Printf("Loaded %d fixture overrides from %s", len(s.overrides), path)
+	return s, nil
+}
+
+// List returns the overrides ordered by match ID
+func (s *Store) List() []Override {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]Override, 0, len(s.overrides))
+	for _, o := range s.overrides {
+		list = append(list, o)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].MatchID < list[j].MatchID })
+	return list
+}
+
+// Get returns the override for a match
+func (s *Store) Get(matchID int) (Override, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.overrides[matchID]
+	return o, ok
+}
+
+// UpdatedAt is when the overrides last changed
+func (s *Store) UpdatedAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.updatedAt
+}
+
+// Audit returns the audit trail newest first, only for matchID unless it is 0
+func (s *Store) Audit(matchID int) []AuditEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := []AuditEntry{}
+	for i := len(s.audit) - 1; i >= 0; i-- {
+		if matchID == 0 || s.audit[i].MatchID == matchID {
+			entries = append(entries, s.audit[i])
+		}
+	}
+	return entries
+}
+
+// Create adds an override. A manual fixture without a match ID gets one.
+func (s *Store) Create(o Override, actor string) (Override, error) {
+	o.normalize()
+	if err := o.validate(); err != nil {
+		return Override{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.overrides[o.MatchID]; ok {
+		return Override{}, ErrExists
+	}
+	now := time.Now().UTC()
+	o.CreatedAt, o.CreatedBy = now, actor
+	o.UpdatedAt, o.UpdatedBy = now, actor
+
+	after := o
+	err := s.change(now, func() {
+		s.overrides[o.MatchID] = o
+	}, AuditEntry{Actor: actor, Action: ActionCreate, MatchID: o.MatchID, After: &after})
+	return o, err
+}
+
+// Update replaces the override for a match
+func (s *Store) Update(matchID int, o Override, actor string) (Override, error) {
+	o.MatchID = matchID
+	o.normalize()
+	if err := o.validate(); err != nil {
+		return Override{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, ok := s.overrides[matchID]
+	if !ok {
+		return Override{}, ErrNotFound
+	}
+	now := time.Now().UTC()
+	o.CreatedAt, o.CreatedBy = before.CreatedAt, before.CreatedBy
+	o.UpdatedAt, o.UpdatedBy = now, actor
+
+	after := o
+	err := s.change(now, func() {
+		s.overrides[matchID] = o
+	}, AuditEntry{Actor: actor, Action: ActionUpdate, MatchID: matchID, Before: &before, After: &after})
+	return o, err
+}
+
+// Delete removes the override for a match
+func (s *Store) Delete(matchID int, actor string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	before, ok := s.overrides[matchID]
+	if !ok {
+		return ErrNotFound
+	}
+	return s.change(time.Now().UTC(), func() {
+		delete(s.overrides, matchID)
+	}, AuditEntry{Actor: actor, Action: ActionDelete, MatchID: matchID, Before: &before})
+}
+
+// change applies a mutation with its audit entry and saves the store,
+// undoing both if the save fails. s.mu must be held.
+func (s *Store) change(now time.Time, mutate func(), entry AuditEntry) error {
+	if s.loadErr != nil {
+		return s.loadErr
+	}
+
+	previous := make(map[int]Override, len(s.overrides))
+	for id, o := range s.overrides {
+		previous[id] = o
+	}
+	auditLen := len(s.audit)
+
+	mutate()
+	entry.Time = now
+	s.audit = append(s.audit, entry)
+
+	if err := s.save(); err != nil {
+		s.overrides = previous
+		s.audit = s.audit[:auditLen]
+		return err
+	}
+	s.updatedAt = now
+	log.Printf("Fixture override %s for match %d by %s", entry.Action, entry.MatchID, entry.Actor)
+	return nil
+}
+
+// save writes the store atomically. s.mu must be held.
+func (s *Store) save() error {
+	file := storeFile{Overrides: make([]Override, 0, len(s.overrides)), Audit: s.audit}
+	for _, o := range s.overrides {
+		file.Overrides = append(file.Overrides, o)
+	}
+	sort.Slice(file.Overrides, func(i, j int) bool { return file.Overrides[i].MatchID < file.Overrides[j].MatchID })
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	os.MkdirAll(filepath.Dir(s.path), 0755)
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to store overrides: %v", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to store overrides: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/fotmob"
+)
+
+// Chain tries providers in priority order and returns the first answer.
+// It remembers which provider each match came from so its details are asked
+// of the same provider first.
+type Chain struct {
+	providers []FixtureProvider
+
+	// Observe, when set, is called after every provider call with its
+	// error, nil on success
+	Observe func(provider string, err error)
+
+	mu     sync.Mutex
+	owners map[int]string
+}
+
+// NewChain creates a chain trying providers in the given order
+func NewChain(providers ...FixtureProvider) *Chain {
+	return &Chain{providers: providers, owners: make(map[int]string)}
+}
+
+func (c *Chain) Name() string {
+	return strings.Join(c.Providers(), ",")
+}
+
+// Providers returns the provider names in priority order
+func (c *Chain) Providers() []string {
+	names := make([]string, len(c.providers))
+	for i, p := range c.providers {
+		names[i] = p.Name()
+	}
+	return names
+}
+
+// Has reports whether the chain includes the named provider
+func (c *Chain) Has(name string) bool {
+	for _, p := range c.providers {
+		if p.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Competitions lists the competitions of every provider that answers
+func (c *Chain) Competitions(ctx context.Context) ([]Competition, error) {
+	var all []Competition
+	var firstErr error
+	for _, p := range c.providers {
+		competitions, err := p.Competitions(ctx)
+		c.observe(p.Name(), err)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		all = append(all, competitions...)
+	}
+	if all == nil && firstErr != nil {
+		return nil, firstErr
+	}
+	return all, nil
+}
+
+func (c *Chain) FetchFixtures(ctx context.Context, competitionID string) ([]interface{}, error) {
+	fixtures, _, err := c.FetchFixturesFrom(ctx, competitionID)
+	return fixtures, err
+}
+
+// FetchFixturesFrom is FetchFixtures that also names the provider that
+// answered. When all fail, the highest priority provider's error is returned.
+func (c *Chain) FetchFixturesFrom(ctx context.Context, competitionID string) ([]interface{}, string, error) {
+	var firstErr error
+	for _, p := range c.providers {
+		fixtures, err := p.FetchFixtures(ctx, competitionID)
+		c.observe(p.Name(), err)
+		if err == nil {
+			c.remember(p.Name(), fixtures)
+			return fixtures, p.Name(), nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if ctx.Err() != nil {
+			return nil, "", ctx.Err()
+		}
+		log.Printf("Fixture provider %s failed: %v", p.Name(), err)
+	}
+	return nil, "", firstErr
+}
+
+// FetchMatchDetails asks the provider the match came from first, then the
+// others in priority order, skipping providers the match ID can't belong to
+func (c *Chain) FetchMatchDetails(ctx context.Context, matchID int) (*fotmob.MatchDetails, error) {
+	var firstErr error
+	for _, p := range c.detailsOrder(matchID) {
+		details, err := p.FetchMatchDetails(ctx, matchID)
+		c.observe(p.Name(), err)
+		if err == nil {
+			return details, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Printf("Fixture provider %s has no details for match %d: %v", p.Name(), matchID, err)
+	}
+	if firstErr == nil {
+		firstErr = fotmob.ErrMatchNotFound
+	}
+	return nil, firstErr
+}
+
+// Owner returns the provider a match was last listed by
+func (c *Chain) Owner(matchID int) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	owner, ok := c.owners[matchID]
+	return owner, ok
+}
+
+func (c *Chain) detailsOrder(matchID int) []FixtureProvider {
+	owner, _ := c.Owner(matchID)
+	ordered := make([]FixtureProvider, 0, len(c.providers))
+	for _, p := range c.providers {
+		if p.Name() == owner {
+			ordered = append(ordered, p)
+		}
+	}
+	for _, p := range c.providers {
+		if p.Name() == owner {
+			continue
+		}
+		if o, ok := p.(matchOwner); ok && !o.Owns(matchID) {
+			continue
+		}
+		ordered = append(ordered, p)
+	}
+	return ordered
+}
+
+func (c *Chain) remember(provider string, fixtures []interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, fixture := range fixtures {
+		matchMap, ok := fixture.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var id int
+		switch v := matchMap["id"].(type) {
+		case string:
+			id, _ = strconv.Atoi(v)
+		case float64:
+			id = int(v)
+		}
+		if id != 0 {
+			c.owners[id] = provider
+		}
+	}
+}
+
+func (c *Chain) observe(provider string, err error) {
+	if c.Observe != nil {
+		c.Observe(provider, err)
+	}
+}
@@ -0,0 +1,533 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/fotmob"
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+)
+
+// Feed match IDs are kept clear of Fotmob's, and of manual fixtures' from
+// 2_000_000_000: a feed's own IDs are offset by feedIDBase, and IDs derived
+// for rows without one start at derivedIDBase
+const (
+	feedIDBase    = 1_000_000_000
+	derivedIDBase = 1_500_000_000
+	feedIDSpan    = 500_000_000
+)
+
+// Feed serves fixtures from a football-data style feed in a file or at an
+// http(s) URL. JSON feeds follow football-data.org's matches response;
+// CSV feeds have a header row with Date, Time, HomeTeam and AwayTeam columns
+// and optionally ID, Div, Round, Venue, Status, FTHG and FTAG. CSV times are
+// kickoff times in Israel; an empty Time means the kickoff is TBC.
+type Feed struct {
+	Location string
+	// TTL is how long a URL feed is kept before it is fetched again. Files
+	// are reread whenever they change.
+	TTL time.Duration
+
+	client *http.Client
+
+	mu           sync.Mutex
+	loadedAt     time.Time
+	modTime      time.Time
+	fixtures     []feedFixture
+	competitions []Competition
+}
+
+// feedFixture is one match read from a feed
+type feedFixture struct {
+	ID          int
+	Competition Competition
+	Kickoff     time.Time
+	TimeTBC     bool
+	Round       string
+	Status      string // football-data status, e.g. SCHEDULED or FINISHED
+	HomeTeam    string
+	AwayTeam    string
+	HomeScore   *int
+	AwayScore   *int
+	Venue       string
+}
+
+// NewFeed creates a feed provider reading location, a path or URL
+func NewFeed(location string, ttl time.Duration) *Feed {
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+	return &Feed{
+		Location: location,
+		TTL:      ttl,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (f *Feed) Name() string {
+	return "feed"
+}
+
+func (f *Feed) Competitions(ctx context.Context) ([]Competition, error) {
+	_, competitions, err := f.load(ctx)
+	return competitions, err
+}
+
+// FetchFixtures returns the feed's matches, all of them for an empty
+// competition ID
+func (f *Feed) FetchFixtures(ctx context.Context, competitionID string) ([]interface{}, error) {
+	fixtures, competitions, err := f.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if competitionID != "" && !hasCompetition(competitions, competitionID) {
+		return nil, fmt.Errorf("%w %q", ErrUnknownCompetition, competitionID)
+	}
+
+	matches := []interface{}{}
+	for _, fixture := range fixtures {
+		if competitionID == "" || fixture.Competition.ID == competitionID {
+			matches = append(matches, fixture.matchMap())
+		}
+	}
+	return matches, nil
+}
+
+// Owns reports whether matchID is in the feed's ID range
+func (f *Feed) Owns(matchID int) bool {
+	return matchID >= feedIDBase && matchID < derivedIDBase+feedIDSpan
+}
+
+func (f *Feed) FetchMatchDetails(ctx context.Context, matchID int) (*fotmob.MatchDetails, error) {
+	if !f.Owns(matchID) {
+		return nil, fotmob.ErrMatchNotFound
+	}
+	fixtures, _, err := f.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, fixture := range fixtures {
+		if fixture.ID == matchID {
+			return fixture.details(), nil
+		}
+	}
+	return nil, fotmob.ErrMatchNotFound
+}
+
+func hasCompetition(competitions []Competition, id string) bool {
+	for _, competition := range competitions {
+		if competition.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// load returns the parsed feed, rereading it when it is due
+func (f *Feed) load(ctx context.Context) ([]feedFixture, []Competition, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	isURL := strings.HasPrefix(f.Location, "http://") || strings.HasPrefix(f.Location, "https://")
+	if isURL && !f.loadedAt.IsZero() && time.Since(f.loadedAt) < f.TTL {
+		return f.fixtures, f.competitions, nil
+	}
+
+	var data []byte
+	var modTime time.Time
+	var err error
+	if isURL {
+		data, err = f.fetch(ctx)
+	} else {
+		var info os.FileInfo
+		if info, err = os.Stat(f.Location); err == nil {
+			if !f.loadedAt.IsZero() && info.ModTime().Equal(f.modTime) {
+				return f.fixtures, f.competitions, nil
+			}
+			modTime = info.ModTime()
+			data, err = os.ReadFile(f.Location)
+		}
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading fixture feed: %w", err)
+	}
+
+	var fixtures []feedFixture
+	if strings.HasSuffix(strings.ToLower(f.Location), ".csv") || !looksLikeJSON(data) {
+		fixtures, err = parseCSVFeed(data)
+	} else {
+		fixtures, err = parseJSONFeed(data)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing fixture feed: %w", err)
+	}
+
+	f.fixtures = fixtures
+	f.competitions = competitionsOf(fixtures, f.Name())
+	f.loadedAt = time.Now()
+	f.modTime = modTime
+	return f.fixtures, f.competitions, nil
+}
+
+func (f *Feed) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", f.Location, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feed answered %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func looksLikeJSON(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+func competitionsOf(fixtures []feedFixture, provider string) []Competition {
+	seen := make(map[string]bool)
+	competitions := []Competition{}
+	for _, fixture := range fixtures {
+		if fixture.Competition.ID == "" || seen[fixture.Competition.ID] {
+			continue
+		}
+		seen[fixture.Competition.ID] = true
+		competition := fixture.Competition
+		competition.Provider = provider
+		competitions = append(competitions, competition)
+	}
+	return competitions
+}
+
+// footballDataCompetition and footballDataMatch are the parts of
+// football-data.org's v4 matches response we read
+type footballDataCompetition struct {
+	ID   int    `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+	Area *struct {
+		Code string `json:"code"`
+	} `json:"area"`
+}
+
+type footballDataMatch struct {
+	ID          int                      `json:"id"`
+	UTCDate     string                   `json:"utcDate"`
+	Status      string                   `json:"status"`
+	Matchday    int                      `json:"matchday"`
+	Venue       string                   `json:"venue"`
+	Competition *footballDataCompetition `json:"competition"`
+	HomeTeam    struct {
+		Name string `json:"name"`
+	} `json:"homeTeam"`
+	AwayTeam struct {
+		Name string `json:"name"`
+	} `json:"awayTeam"`
+	Score struct {
+		FullTime struct {
+			Home *int `json:"home"`
+			Away *int `json:"away"`
+		} `json:"fullTime"`
+	} `json:"score"`
+}
+
+func (c *footballDataCompetition) competition() Competition {
+	if c == nil {
+		return Competition{}
+	}
+	competition := Competition{ID: c.Code, Name: c.Name}
+	if competition.ID == "" && c.ID != 0 {
+		competition.ID = strconv.Itoa(c.ID)
+	}
+	if c.Area != nil {
+		competition.Country = c.Area.Code
+	}
+	return competition
+}
+
+// parseJSONFeed reads a football-data.org style response: an object with a
+// "matches" array and optionally a "competition", or a bare array of matches
+func parseJSONFeed(data []byte) ([]feedFixture, error) {
+	var feed struct {
+		Competition *footballDataCompetition `json:"competition"`
+		Matches     []footballDataMatch      `json:"matches"`
+	}
+	if bytes.TrimSpace(data)[0] == '[' {
+		if err := json.Unmarshal(data, &feed.Matches); err != nil {
+			return nil, err
+		}
+	} else if err := json.Unmarshal(data, &feed); err != nil {
+		return nil, err
+	}
+
+	fixtures := make([]feedFixture, 0, len(feed.Matches))
+	for i, match := range feed.Matches {
+		kickoff, err := fotmob.ParseKickoff(match.UTCDate)
+		if err != nil {
+			return nil, fmt.Errorf("match %d: %v", i+1, err)
+		}
+
+		fixture := feedFixture{
+			ID:        feedID(match.ID),
+			Kickoff:   kickoff,
+			Status:    strings.ToUpper(match.Status),
+			HomeTeam:  match.HomeTeam.Name,
+			AwayTeam:  match.AwayTeam.Name,
+			HomeScore: match.Score.FullTime.Home,
+			AwayScore: match.Score.FullTime.Away,
+			Venue:     match.Venue,
+		}
+		if match.Competition != nil {
+			fixture.Competition = match.Competition.competition()
+		} else {
+			fixture.Competition = feed.Competition.competition()
+		}
+		if match.Matchday != 0 {
+			fixture.Round = strconv.Itoa(match.Matchday)
+		}
+		if fixture.ID == 0 {
+			fixture.ID = derivedID(fixture)
+		}
+		fixtures = append(fixtures, fixture)
+	}
+	return fixtures, nil
+}
+
+var csvDateLayouts = []string{"02/01/2006", "02/01/06", "2006-01-02"}
+
+// parseCSVFeed reads a football-data.co.uk style CSV
+func parseCSVFeed(data []byte) ([]feedFixture, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return []feedFixture{}, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"date", "hometeam", "awayteam"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing %s column", required)
+		}
+	}
+	field := func(record []string, names ...string) string {
+		for _, name := range names {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+		}
+		return ""
+	}
+
+	fixtures := make([]feedFixture, 0, len(records)-1)
+	for line, record := range records[1:] {
+		fixture := feedFixture{
+			HomeTeam: field(record, "hometeam"),
+			AwayTeam: field(record, "awayteam"),
+			Round:    field(record, "round", "matchday"),
+			Venue:    field(record, "venue"),
+			Status:   strings.ToUpper(field(record, "status")),
+		}
+		if fixture.HomeTeam == "" && fixture.AwayTeam == "" {
+			continue
+		}
+		if div := field(record, "div", "competition"); div != "" {
+			fixture.Competition = Competition{ID: div, Name: div}
+		}
+
+		day, err := parseCSVDate(field(record, "date"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line+2, err)
+		}
+		clock := field(record, "time")
+		if clock == "" {
+			fixture.TimeTBC = true
+			clock = "00:00"
+		}
+		kickoff, err := time.ParseInLocation("2006-01-02 15:04", day+" "+clock, i18n.Jerusalem)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid time %q", line+2, clock)
+		}
+		fixture.Kickoff = kickoff.UTC()
+
+		fixture.HomeScore = parseGoals(field(record, "fthg"))
+		fixture.AwayScore = parseGoals(field(record, "ftag"))
+		if fixture.Status == "" {
+			fixture.Status = "SCHEDULED"
+			if fixture.HomeScore != nil && fixture.AwayScore != nil {
+				fixture.Status = "FINISHED"
+			}
+		}
+
+		id, _ := strconv.Atoi(field(record, "id"))
+		if fixture.ID = feedID(id); fixture.ID == 0 {
+			fixture.ID = derivedID(fixture)
+		}
+		fixtures = append(fixtures, fixture)
+	}
+	return fixtures, nil
+}
+
+func parseCSVDate(value string) (string, error) {
+	for _, layout := range csvDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format("2006-01-02"), nil
+		}
+	}
+	return "", fmt.Errorf("invalid date %q", value)
+}
+
+func parseGoals(value string) *int {
+	goals, err := strconv.Atoi(value)
+	if err != nil {
+		return nil
+	}
+	return &goals
+}
+
+// feedID moves a feed's own match ID into the feed's range, 0 if it has none
+func feedID(id int) int {
+	if id <= 0 {
+		return 0
+	}
+	return feedIDBase + id%feedIDSpan
+}
+
+// derivedID gives a row without an ID a stable one from its date and teams
+func derivedID(fixture feedFixture) int {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s|%s|%s", fixture.Kickoff.Format("2006-01-02"), teams.Normalize(fixture.HomeTeam), teams.Normalize(fixture.AwayTeam))
+	return derivedIDBase + int(h.Sum32()%feedIDSpan)
+}
+
+// flags maps a football-data status to Fotmob's status flags and reason
+func (fixture feedFixture) flags() (started, finished, cancelled bool, reason string) {
+	switch fixture.Status {
+	case "IN_PLAY", "LIVE":
+		return true, false, false, ""
+	case "PAUSED":
+		return true, false, false, "HT"
+	case "FINISHED", "AWARDED":
+		return true, true, false, "FT"
+	case "POSTPONED":
+		return false, false, false, "PP"
+	case "SUSPENDED":
+		return true, false, false, "AB"
+	case "CANCELLED", "CANCELED":
+		return false, false, true, ""
+	}
+	return false, false, false, ""
+}
+
+func (fixture feedFixture) score() string {
+	if fixture.HomeScore == nil || fixture.AwayScore == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d - %d", *fixture.HomeScore, *fixture.AwayScore)
+}
+
+// matchMap renders the fixture in Fotmob's league match shape. Team IDs are
+// left out: the feed's IDs aren't Fotmob's, so clubs are matched by name.
+func (fixture feedFixture) matchMap() map[string]interface{} {
+	started, finished, cancelled, reason := fixture.flags()
+	status := map[string]interface{}{
+		"utcTime":   fixture.Kickoff.Format(time.RFC3339),
+		"started":   started,
+		"finished":  finished,
+		"cancelled": cancelled,
+	}
+	if reason != "" {
+		status["reason"] = map[string]interface{}{"short": reason}
+	}
+	if score := fixture.score(); score != "" {
+		status["scoreStr"] = score
+	}
+
+	match := map[string]interface{}{
+		"id":     strconv.Itoa(fixture.ID),
+		"home":   map[string]interface{}{"name": fixture.HomeTeam},
+		"away":   map[string]interface{}{"name": fixture.AwayTeam},
+		"status": status,
+		"source": "feed",
+	}
+	if fixture.TimeTBC {
+		match["timeTBD"] = true
+	}
+	if fixture.Round != "" {
+		match["round"] = fixture.Round
+	}
+	if fixture.Venue != "" {
+		match["venue"] = fixture.Venue
+	}
+	if fixture.Competition.Name != "" {
+		match["competition"] = fixture.Competition.Name
+	}
+	return match
+}
+
+// details builds match details from the fixture alone; feeds carry no
+// lineups, events or statistics
+func (fixture feedFixture) details() *fotmob.MatchDetails {
+	started, finished, cancelled, reason := fixture.flags()
+	details := &fotmob.MatchDetails{
+		ID:          fixture.ID,
+		Competition: fixture.Competition.Name,
+		Round:       fixture.Round,
+		UTCTime:     fixture.Kickoff.Format(time.RFC3339),
+		Status: fotmob.MatchStatus{
+			Started:   started,
+			Finished:  finished,
+			Cancelled: cancelled,
+			Score:     fixture.score(),
+			Short:     reason,
+		},
+		HomeTeam:      feedTeam(fixture.HomeTeam, fixture.HomeScore),
+		AwayTeam:      feedTeam(fixture.AwayTeam, fixture.AwayScore),
+		Goals:         []fotmob.GoalEvent{},
+		Cards:         []fotmob.CardEvent{},
+		Substitutions: []fotmob.SubstitutionEvent{},
+		Stats:         []fotmob.StatGroup{},
+		Source:        "feed",
+	}
+	if fixture.Venue != "" {
+		details.Venue = &fotmob.MatchVenue{Name: fixture.Venue}
+	}
+	return details
+}
+
+func feedTeam(name string, score *int) fotmob.MatchTeam {
+	team := fotmob.MatchTeam{Name: name, Score: score}
+	if club, ok := teams.Default.Resolve(0, name); ok {
+		if club.ID != 0 {
+			team.ID = club.ID
+			team.LogoURL = teams.LogoURL(club.ID)
+		}
+		team.Club = &club
+	}
+	return team
+}
@@ -0,0 +1,92 @@
+// Package provider abstracts where fixtures and match details come from, so
+// the app can keep serving when Fotmob is unavailable. Providers return raw
+// fixtures in Fotmob's league match shape, which the filters, localization
+// and typed Match in package fotmob already understand.
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/fotmob"
+)
+
+// ErrUnknownCompetition is returned for a competition a provider doesn't carry
+var ErrUnknownCompetition = errors.New("unknown competition")
+
+// Competition is a league or cup a provider has fixtures for
+type Competition struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Country  string `json:"country,omitempty"`
+	Provider string `json:"provider"`
+}
+
+// FixtureProvider is a source of fixtures and match details. An empty
+// competition ID means the provider's default, the Israeli Premier League.
+type FixtureProvider interface {
+	Name() string
+	Competitions(ctx context.Context) ([]Competition, error)
+	// FetchFixtures returns raw matches in Fotmob's league match shape, each
+	// with a "source" key naming the provider
+	FetchFixtures(ctx context.Context, competitionID string) ([]interface{}, error)
+	FetchMatchDetails(ctx context.Context, matchID int) (*fotmob.MatchDetails, error)
+}
+
+// matchOwner is implemented by providers that can tell their own match IDs
+// apart. The chain only asks them for the details of their own matches.
+type matchOwner interface {
+	Owns(matchID int) bool
+}
+
+// israeliLeagueID is Fotmob's ID for the Israeli Premier League
+const israeliLeagueID = "127"
+
+// Fotmob serves fixtures from the Fotmob API
+type Fotmob struct {
+	*fotmob.FotmobClient
+}
+
+// NewFotmob wraps client as a FixtureProvider
+func NewFotmob(client *fotmob.FotmobClient) *Fotmob {
+	return &Fotmob{FotmobClient: client}
+}
+
+func (f *Fotmob) Name() string {
+	return "fotmob"
+}
+
+// Owns reports whether matchID can be a Fotmob match. Feed and manual
+// fixtures are numbered from 1_000_000_000.
+func (f *Fotmob) Owns(matchID int) bool {
+	return matchID > 0 && matchID < feedIDBase
+}
+
+// Competitions lists the leagues the client knows how to fetch
+func (f *Fotmob) Competitions(ctx context.Context) ([]Competition, error) {
+	return []Competition{{ID: israeliLeagueID, Name: "Ligat Ha'Al", Country: "ISR", Provider: f.Name()}}, nil
+}
+
+// FetchFixtures returns every match of the league's current season
+func (f *Fotmob) FetchFixtures(ctx context.Context, competitionID string) ([]interface{}, error) {
+	if competitionID != "" && competitionID != israeliLeagueID {
+		return nil, fmt.Errorf("%w %q", ErrUnknownCompetition, competitionID)
+	}
+
+	matchesData, err := f.FetchIsraeliLeagueMatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matches, ok := matchesData.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid matches data format")
+	}
+
+	for _, match := range matches {
+		if matchMap, ok := match.(map[string]interface{}); ok {
+			matchMap["source"] = f.Name()
+		}
+	}
+	return matches, nil
+}
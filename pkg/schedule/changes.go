@@ -0,0 +1,139 @@
+package schedule
+
+import (
+	"sync"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/fotmob"
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+)
+
+// Types of change
+const (
+	Added       = "added"
+	Rescheduled = "rescheduled"
+)
+
+// Change is a fixture that appeared at a venue or moved to, from or within
+// it since the previous fetch
+type Change struct {
+	Type       string       `json:"type"`
+	DetectedAt time.Time    `json:"detectedAt"`
+	Match      fotmob.Match `json:"match"`
+	// PreviousKickoff and PreviousVenue are where and when a rescheduled
+	// fixture was before
+	PreviousKickoff *time.Time `json:"previousKickoff,omitempty"`
+	PreviousVenue   string     `json:"previousVenue,omitempty"`
+	// Conflicts are the clashes at the venue this change created
+	Conflicts []Conflict `json:"conflicts,omitempty"`
+}
+
+// slot is where and when a fixture is scheduled, and how the venue was
+// decided (see fotmob.DetectVenue)
+type slot struct {
+	kickoff     time.Time
+	venue       string
+	venueSource string
+}
+
+// guessed reports whether the slot's venue is only the home club's ground
+// or unknown, rather than given by a provider, the match details or an admin
+func (s slot) guessed() bool {
+	return s.venueSource == fotmob.VenueFromHomeTeam || s.venueSource == fotmob.VenueUnknown
+}
+
+// observed is the last fixture list from one provider: the slot of each
+// upcoming fixture and the conflicts already reported
+type observed struct {
+	slots map[int]slot
+	pairs map[[2]int]bool
+}
+
+// Watcher compares successive fixture lists for changes at one venue.
+// Providers number matches differently, so each is compared only with its
+// own previous list, and the first list from a provider is the baseline
+// that nothing is reported for.
+type Watcher struct {
+	venue  string
+	window time.Duration
+
+	mu        sync.Mutex
+	providers map[string]*observed
+}
+
+// NewWatcher watches venue, flagging clashes within window
+func NewWatcher(venue string, window time.Duration) *Watcher {
+	return &Watcher{venue: teams.CanonicalVenue(venue), window: window, providers: make(map[string]*observed)}
+}
+
+// Observe records a fixture list freshly fetched from provider and returns
+// the changes at the venue since that provider's last one
+func (w *Watcher) Observe(provider string, fixtures []interface{}) []Change {
+	now := time.Now()
+	all := upcoming(fixtures, i18n.DefaultLocale)
+	var atVenue []fotmob.Match
+	for _, match := range all {
+		if teams.SameVenue(match.Venue, w.venue) {
+			atVenue = append(atVenue, match)
+		}
+	}
+	conflicts := Find(atVenue, w.window)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var changes []Change
+	last, ok := w.providers[provider]
+	baseline := !ok
+	if baseline {
+		last = &observed{}
+		w.providers[provider] = last
+	}
+	slots := make(map[int]slot, len(all))
+	for _, match := range all {
+		current := slot{*match.Kickoff, teams.CanonicalVenue(match.Venue), match.VenueSource}
+		previous, seen := last.slots[match.ID]
+		slots[match.ID] = current
+		if baseline {
+			continue
+		}
+
+		// A guessed venue replaced by the real one, or the other way round,
+		// isn't a move
+		if seen && (previous.guessed() || current.guessed()) {
+			previous.venue = current.venue
+		}
+		here := teams.SameVenue(current.venue, w.venue)
+		wasHere := seen && teams.SameVenue(previous.venue, w.venue)
+		change := Change{DetectedAt: now, Match: match}
+		switch {
+		case !here && !wasHere:
+			continue
+		case !seen:
+			change.Type = Added
+		case !previous.kickoff.Equal(current.kickoff) || previous.venue != current.venue:
+			change.Type = Rescheduled
+			kickoff := previous.kickoff
+			change.PreviousKickoff = &kickoff
+			change.PreviousVenue = previous.venue
+		default:
+			continue
+		}
+
+		for _, conflict := range conflicts {
+			if conflict.Involves(match.ID) && !last.pairs[conflict.key()] {
+				change.Conflicts = append(change.Conflicts, conflict)
+			}
+		}
+		changes = append(changes, change)
+	}
+
+	// Fixtures no longer upcoming are forgotten
+	last.slots = slots
+	last.pairs = make(map[[2]int]bool, len(conflicts))
+	for _, conflict := range conflicts {
+		last.pairs[conflict.key()] = true
+	}
+	return changes
+}
@@ -0,0 +1,124 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/fotmob"
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+)
+
+// DefaultWindow flags fixtures on the same or consecutive days
+const DefaultWindow = 36 * time.Hour
+
+// Kinds of conflict, most serious first
+const (
+	// DoubleBooking is two kickoffs less than a MatchSlot apart
+	DoubleBooking = "double-booking"
+	// SameDay is two matches on the same Israeli calendar day
+	SameDay = "same-day"
+	// BackToBack is two matches on different days within the window
+	BackToBack = "back-to-back"
+)
+
+// Conflict is a pair of fixtures at one venue closer together than the
+// window, earlier match first
+type Conflict struct {
+	Kind  string `json:"kind"`
+	Venue string `json:"venue"`
+	// GapMinutes is the time between the kickoffs, counted in whole days
+	// when either kickoff time is still to be confirmed
+	GapMinutes int             `json:"gapMinutes"`
+	Matches    [2]fotmob.Match `json:"matches"`
+}
+
+// Involves reports whether matchID is one of the conflicting fixtures
+func (c Conflict) Involves(matchID int) bool {
+	return c.Matches[0].ID == matchID || c.Matches[1].ID == matchID
+}
+
+func (c Conflict) key() [2]int {
+	return [2]int{c.Matches[0].ID, c.Matches[1].ID}
+}
+
+// Report is the conflict analysis of one venue's schedule
+type Report struct {
+	Venue         string `json:"venue"`
+	Slug          string `json:"slug"`
+	WindowMinutes int    `json:"windowMinutes"`
+	// Matches counts the fixtures still to be played at the venue
+	Matches   int        `json:"matches"`
+	Conflicts []Conflict `json:"conflicts"`
+}
+
+// Analyze finds the conflicts among the fixtures still to be played at
+// venue
+func Analyze(fixtures []interface{}, venue string, window time.Duration, locale i18n.Locale) Report {
+	matches := ForVenue(fixtures, venue, locale)
+	slug, _ := teams.VenueSlug(venue)
+	return Report{
+		Venue:         teams.CanonicalVenue(venue),
+		Slug:          slug,
+		WindowMinutes: int(window / time.Minute),
+		Matches:       len(matches),
+		Conflicts:     Find(matches, window),
+	}
+}
+
+// Find returns every pair of matches, all at one venue and sorted by
+// kickoff, that are within window of each other
+func Find(matches []fotmob.Match, window time.Duration) []Conflict {
+	conflicts := []Conflict{}
+	for i := range matches {
+		for j := i + 1; j < len(matches); j++ {
+			kind, gap, ok := classify(matches[i], matches[j], window)
+			if !ok {
+				continue
+			}
+			conflicts = append(conflicts, Conflict{
+				Kind:       kind,
+				Venue:      matches[i].Venue,
+				GapMinutes: int(gap / time.Minute),
+				Matches:    [2]fotmob.Match{matches[i], matches[j]},
+			})
+		}
+	}
+	return conflicts
+}
+
+// classify says how two matches clash, if they are within window. Only the
+// dates of fixtures whose time is to be confirmed are compared, so they are
+// never a double booking.
+func classify(a, b fotmob.Match, window time.Duration) (string, time.Duration, bool) {
+	dayA, dayB := calendarDay(*a.Kickoff), calendarDay(*b.Kickoff)
+	sameDay := dayA.Equal(dayB)
+
+	var gap time.Duration
+	if a.TimeTBC || b.TimeTBC {
+		gap = dayB.Sub(dayA)
+	} else {
+		gap = b.Kickoff.Sub(*a.Kickoff)
+	}
+	if gap < 0 {
+		gap = -gap
+	}
+	if gap > window {
+		return "", 0, false
+	}
+
+	switch {
+	case !a.TimeTBC && !b.TimeTBC && gap < MatchSlot:
+		return DoubleBooking, gap, true
+	case sameDay:
+		return SameDay, gap, true
+	default:
+		return BackToBack, gap, true
+	}
+}
+
+// calendarDay returns the Israeli date of t as midnight UTC, so days are
+// always 24 hours apart
+func calendarDay(t time.Time) time.Time {
+	y, m, d := t.In(i18n.Jerusalem).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
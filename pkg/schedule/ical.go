@@ -0,0 +1,139 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+)
+
+const icsTime = "20060102T150405Z"
+
+// FreeBusyICS renders busy windows as an iCalendar feed (RFC 5545): one
+// VFREEBUSY listing every window, for scheduling tools, and an opaque
+// VEVENT per window so calendar apps show them. name and address are the
+// venue's in locale, and stamp dates the feed.
+func FreeBusyICS(slug, name, address string, windows []BusyWindow, stamp time.Time, locale i18n.Locale) []byte {
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		b.WriteString(foldICS(fmt.Sprintf(format, args...)))
+	}
+	stamp = stamp.UTC()
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Sammy-PO//Busy windows//%s", strings.ToUpper(string(locale)))
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:%s", escapeICS(fmt.Sprintf(i18n.T(locale, "busy.calendar"), name)))
+	line("X-WR-TIMEZONE:Asia/Jerusalem")
+
+	if len(windows) > 0 {
+		line("BEGIN:VFREEBUSY")
+		line("UID:freebusy-%s@sammy-po", slug)
+		line("DTSTAMP:%s", stamp.Format(icsTime))
+		line("DTSTART:%s", earliestStart(windows).Format(icsTime))
+		line("DTEND:%s", latestEnd(windows).Format(icsTime))
+		for _, window := range windows {
+			kind := "BUSY"
+			if window.Tentative {
+				kind = "BUSY-TENTATIVE"
+			}
+			line("FREEBUSY;FBTYPE=%s:%s/%s", kind, window.Start.UTC().Format(icsTime), window.End.UTC().Format(icsTime))
+		}
+		line("END:VFREEBUSY")
+	}
+
+	for _, window := range windows {
+		match := window.Match
+		line("BEGIN:VEVENT")
+		line("UID:busy-%d@sammy-po", match.ID)
+		line("DTSTAMP:%s", stamp.Format(icsTime))
+		if window.Tentative {
+			line("DTSTART;VALUE=DATE:%s", window.Start.In(i18n.Jerusalem).Format("20060102"))
+			line("DTEND;VALUE=DATE:%s", window.End.In(i18n.Jerusalem).Format("20060102"))
+			line("STATUS:TENTATIVE")
+		} else {
+			line("DTSTART:%s", window.Start.UTC().Format(icsTime))
+			line("DTEND:%s", window.End.UTC().Format(icsTime))
+			line("STATUS:CONFIRMED")
+		}
+		line("TRANSP:OPAQUE")
+		line("SUMMARY:%s", escapeICS(fmt.Sprintf(i18n.T(locale, "busy.summary"), teamName(match.HomeTeam, locale), teamName(match.AwayTeam, locale))))
+		line("LOCATION:%s", escapeICS(address))
+		line("DESCRIPTION:%s", escapeICS(describeWindow(window, locale)))
+		line("CATEGORIES:%s", strings.ToUpper(window.Level))
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// describeWindow lists the gates, kickoff and clearance times and the
+// expected crowd
+func describeWindow(window BusyWindow, locale i18n.Locale) string {
+	crowd := fmt.Sprintf(i18n.T(locale, "busy.crowd"), window.ExpectedCrowd, i18n.T(locale, "busy.level."+window.Level))
+	if window.Tentative {
+		return i18n.T(locale, "status.tbc") + "\n" + crowd
+	}
+	return strings.Join([]string{
+		i18n.T(locale, "busy.gatesOpen") + ": " + i18n.FormatTime(locale, *window.GatesOpen),
+		i18n.T(locale, "busy.kickoff") + ": " + i18n.FormatTime(locale, *window.Kickoff),
+		i18n.T(locale, "busy.clearance") + ": " + i18n.FormatTime(locale, *window.Clearance),
+		crowd,
+	}, "\n")
+}
+
+// earliestStart and latestEnd bound the windows, which are ordered by
+// kickoff rather than by start or end
+func earliestStart(windows []BusyWindow) time.Time {
+	start := windows[0].Start
+	for _, window := range windows[1:] {
+		if window.Start.Before(start) {
+			start = window.Start
+		}
+	}
+	return start.UTC()
+}
+
+func latestEnd(windows []BusyWindow) time.Time {
+	var end time.Time
+	for _, window := range windows {
+		if window.End.After(end) {
+			end = window.End
+		}
+	}
+	return end.UTC()
+}
+
+func teamName(name string, locale i18n.Locale) string {
+	if team, ok := teams.Default.LookupName(name); ok {
+		return i18n.TeamName(locale, team)
+	}
+	return name
+}
+
+// escapeICS escapes a TEXT value
+func escapeICS(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// foldICS ends a content line with CRLF, folding it into lines of at most
+// 75 octets without splitting a UTF-8 character
+func foldICS(s string) string {
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,236 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/fotmob"
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+)
+
+// Congestion levels, by expected crowd against capacity
+const (
+	CrowdLow      = "low"
+	CrowdModerate = "moderate"
+	CrowdHigh     = "high"
+)
+
+// Duration is a time.Duration written in JSON as a string such as "90m"
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"90m\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Offsets place a busy window around a kickoff: from GatesOpen before it
+// until Clearance after it, when the crowd has left the area
+type Offsets struct {
+	GatesOpen Duration `json:"gatesOpen"`
+	Clearance Duration `json:"clearance"`
+}
+
+// CrowdBand adds to the offsets of fixtures expecting at least MinCrowd
+type CrowdBand struct {
+	MinCrowd int `json:"minCrowd"`
+	Offsets
+}
+
+// Occupancy turns fixtures into the windows the stadium and the streets
+// around it are busy. Competitions and Crowds are keyed by competition
+// and home club name.
+type Occupancy struct {
+	// Capacity rates expected crowds; zero leaves every fixture moderate
+	Capacity     int                `json:"capacity"`
+	Default      Offsets            `json:"default"`
+	Competitions map[string]Offsets `json:"competitions"`
+	// CrowdBands are tried from the largest MinCrowd down; the first that
+	// applies is used
+	CrowdBands []CrowdBand `json:"crowdBands"`
+	// Crowds is the usual attendance of each club's home games, and
+	// DefaultCrowd that of anyone else's
+	Crowds       map[string]int `json:"crowds"`
+	DefaultCrowd int            `json:"defaultCrowd"`
+	// DerbyCrowd is expected when both sides call the venue home
+	DerbyCrowd int `json:"derbyCrowd"`
+}
+
+// DefaultOccupancy is a model of Sammy Ofer on a match day, less its
+// capacity
+var DefaultOccupancy = Occupancy{
+	Default: Offsets{GatesOpen: Duration(90 * time.Minute), Clearance: Duration(165 * time.Minute)},
+	Competitions: map[string]Offsets{
+		"Ligat Ha'Al":            {GatesOpen: Duration(90 * time.Minute), Clearance: Duration(165 * time.Minute)},
+		"State Cup":              {GatesOpen: Duration(90 * time.Minute), Clearance: Duration(210 * time.Minute)},
+		"Toto Cup":               {GatesOpen: Duration(60 * time.Minute), Clearance: Duration(150 * time.Minute)},
+		"UEFA Champions League":  {GatesOpen: Duration(120 * time.Minute), Clearance: Duration(195 * time.Minute)},
+		"UEFA Europa League":     {GatesOpen: Duration(120 * time.Minute), Clearance: Duration(195 * time.Minute)},
+		"UEFA Conference League": {GatesOpen: Duration(120 * time.Minute), Clearance: Duration(195 * time.Minute)},
+	},
+	CrowdBands: []CrowdBand{
+		{MinCrowd: 25000, Offsets: Offsets{GatesOpen: Duration(30 * time.Minute), Clearance: Duration(30 * time.Minute)}},
+		{MinCrowd: 15000, Offsets: Offsets{GatesOpen: Duration(15 * time.Minute), Clearance: Duration(15 * time.Minute)}},
+	},
+	Crowds: map[string]int{
+		"Maccabi Haifa": 25000,
+		"Hapoel Haifa":  8000,
+	},
+	DefaultCrowd: 5000,
+	DerbyCrowd:   30000,
+}
+
+// LoadOccupancy reads a JSON model from path over DefaultOccupancy: the
+// competitions and crowds it names replace the default ones of the same
+// name, and fields it leaves out keep their defaults
+func LoadOccupancy(path string) (Occupancy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return DefaultOccupancy, err
+	}
+	model := DefaultOccupancy
+	model.Competitions, model.Crowds = nil, nil
+	if err := json.Unmarshal(data, &model); err != nil {
+		return DefaultOccupancy, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	model.Competitions = mergeByName(DefaultOccupancy.Competitions, model.Competitions)
+	model.Crowds = mergeByName(DefaultOccupancy.Crowds, model.Crowds)
+	return model, nil
+}
+
+// mergeByName copies defaults and then overrides, dropping defaults whose
+// name normalizes the same as an override's
+func mergeByName[V any](defaults, overrides map[string]V) map[string]V {
+	merged := make(map[string]V, len(defaults)+len(overrides))
+	for name, value := range defaults {
+		merged[name] = value
+	}
+	for name, value := range overrides {
+		for existing := range merged {
+			if teams.Normalize(existing) == teams.Normalize(name) {
+				delete(merged, existing)
+			}
+		}
+		merged[name] = value
+	}
+	return merged
+}
+
+// BusyWindow is the time a fixture keeps the stadium's surroundings busy.
+// A fixture whose kickoff time is still to be confirmed is tentatively
+// busy for its whole Israeli calendar day, and has no Kickoff.
+type BusyWindow struct {
+	Match         fotmob.Match `json:"match"`
+	Start         time.Time    `json:"start"`
+	GatesOpen     *time.Time   `json:"gatesOpen"`
+	Kickoff       *time.Time   `json:"kickoff"`
+	Clearance     *time.Time   `json:"clearance"`
+	End           time.Time    `json:"end"`
+	Tentative     bool         `json:"tentative"`
+	ExpectedCrowd int          `json:"expectedCrowd"`
+	Level         string       `json:"level"`
+}
+
+// Windows returns the busy windows of matches, all at the modelled venue
+func (o Occupancy) Windows(matches []fotmob.Match) []BusyWindow {
+	windows := []BusyWindow{}
+	for _, match := range matches {
+		if match.Kickoff == nil {
+			continue
+		}
+		crowd := o.expectedCrowd(match)
+		window := BusyWindow{Match: match, ExpectedCrowd: crowd, Level: o.level(crowd)}
+
+		if match.TimeTBC {
+			day := match.Kickoff.In(i18n.Jerusalem)
+			window.Start = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, i18n.Jerusalem).UTC()
+			window.End = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, i18n.Jerusalem).UTC()
+			window.Tentative = true
+		} else {
+			offsets := o.offsets(match, crowd)
+			kickoff := *match.Kickoff
+			gatesOpen := kickoff.Add(-time.Duration(offsets.GatesOpen))
+			clearance := kickoff.Add(time.Duration(offsets.Clearance))
+			window.Start, window.End = gatesOpen, clearance
+			window.GatesOpen, window.Kickoff, window.Clearance = &gatesOpen, &kickoff, &clearance
+		}
+		windows = append(windows, window)
+	}
+	return windows
+}
+
+// offsets returns the competition's offsets plus those of the crowd band
+func (o Occupancy) offsets(match fotmob.Match, crowd int) Offsets {
+	offsets := o.Default
+	for name, competition := range o.Competitions {
+		if teams.Normalize(name) == teams.Normalize(match.Competition) {
+			offsets = competition
+			break
+		}
+	}
+
+	var band *CrowdBand
+	for i := range o.CrowdBands {
+		if crowd >= o.CrowdBands[i].MinCrowd && (band == nil || o.CrowdBands[i].MinCrowd > band.MinCrowd) {
+			band = &o.CrowdBands[i]
+		}
+	}
+	if band != nil {
+		offsets.GatesOpen += band.GatesOpen
+		offsets.Clearance += band.Clearance
+	}
+	return offsets
+}
+
+// expectedCrowd estimates a fixture's attendance from its home club, or
+// DerbyCrowd when both clubs play their home games at the venue
+func (o Occupancy) expectedCrowd(match fotmob.Match) int {
+	home, homeKnown := teams.Default.LookupName(match.HomeTeam)
+	away, awayKnown := teams.Default.LookupName(match.AwayTeam)
+
+	crowd := o.DefaultCrowd
+	if homeKnown && awayKnown && o.DerbyCrowd > 0 && teams.SameVenue(home.HomeVenue, match.Venue) && teams.SameVenue(away.HomeVenue, match.Venue) {
+		crowd = o.DerbyCrowd
+	} else if homeKnown {
+		for name, c := range o.Crowds {
+			if teams.Normalize(name) == teams.Normalize(home.Name) {
+				crowd = c
+				break
+			}
+		}
+	}
+	if o.Capacity > 0 && crowd > o.Capacity {
+		crowd = o.Capacity
+	}
+	return crowd
+}
+
+// level rates a crowd against the capacity
+func (o Occupancy) level(crowd int) string {
+	if o.Capacity <= 0 {
+		return CrowdModerate
+	}
+	switch share := float64(crowd) / float64(o.Capacity); {
+	case share < 0.35:
+		return CrowdLow
+	case share < 0.7:
+		return CrowdModerate
+	default:
+		return CrowdHigh
+	}
+}
@@ -0,0 +1,57 @@
+// Package schedule looks at the fixtures played at one venue: which of them
+// clash, and how the schedule changes between fetches.
+package schedule
+
+import (
+	"sort"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/fotmob"
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+)
+
+// MatchSlot is how long a match occupies its stadium from kickoff
+const MatchSlot = 3 * time.Hour
+
+// offSchedule are the statuses of fixtures that no longer take up the
+// stadium
+var offSchedule = map[string]bool{
+	"finished":  true,
+	"postponed": true,
+	"cancelled": true,
+	"abandoned": true,
+}
+
+// ForVenue returns the fixtures at venue that are still to be played or
+// under way, as typed matches soonest first. fixtures are raw league
+// matches with overrides already applied.
+func ForVenue(fixtures []interface{}, venue string, locale i18n.Locale) []fotmob.Match {
+	var matches []fotmob.Match
+	for _, match := range upcoming(fixtures, locale) {
+		if teams.SameVenue(match.Venue, venue) {
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+// upcoming returns every fixture still to be played or under way, soonest
+// first
+func upcoming(fixtures []interface{}, locale i18n.Locale) []fotmob.Match {
+	since := time.Now().Add(-MatchSlot)
+	var matches []fotmob.Match
+	for _, fixture := range fixtures {
+		matchMap, ok := fixture.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		match := fotmob.NewMatch(matchMap, locale)
+		if match.ID == 0 || match.Kickoff == nil || match.Kickoff.Before(since) || offSchedule[match.Status] {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Kickoff.Before(*matches[j].Kickoff) })
+	return matches
+}
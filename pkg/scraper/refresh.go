@@ -0,0 +1,211 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/outbound"
+)
+
+// RefreshToken refreshes the token after the stored one went stale
+func RefreshToken(ctx context.Context) {
+	log.Println("Refreshing token...")
+
+	// Ensure responses directory exists
+	os.MkdirAll("responses", 0755)
+
+	if _, _, err := Refresh(ctx, "stale-token"); err != nil {
+		log.Printf("All automatic token refresh methods failed: %v", err)
+	}
+}
+
+// ManualRefreshToken refreshes the token on request and returns it
+func ManualRefreshToken(ctx context.Context) (string, error) {
+	log.Println("Manually refreshing token...")
+
+	// Ensure responses directory exists
+	os.MkdirAll("responses", 0755)
+
+	token, _, err := Refresh(ctx, "manual")
+	return token, err
+}
+
+// IsTokenFresh reports whether the token file was written in the last two minutes
+func IsTokenFresh() bool {
+	// Check if the token file exists and is fresh
+	tokenFile := filepath.Join("responses", "x-mas-token.txt")
+	info, err := os.Stat(tokenFile)
+	if err != nil {
+		return false // File doesn't exist or can't be accessed
+	}
+	
+	// Check if the file is less than 2 minutes old
+	return time.Since(info.ModTime()) < 2*time.Minute
+}
+
+// GetTokenDirectHTTP scrapes the token from the Fotmob homepage without a browser
+func GetTokenDirectHTTP(ctx context.Context) (string, error) {
+	log.Println("Getting token via direct HTTP request...")
+
+	client := &http.Client{
+		Timeout: 15 * time.Second, // Increased timeout slightly
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://www.fotmob.com", nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %v", err)
+	}
+
+	// Add more realistic headers
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7")
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	req.Header.Set("Sec-Ch-Ua", `"Google Chrome";v="123", "Not:A-Brand";v="8", "Chromium";v="123"`)
+	req.Header.Set("Sec-Ch-Ua-Mobile", "?0")
+	req.Header.Set("Sec-Ch-Ua-Platform", `"Windows"`)
+	req.Header.Set("Sec-Fetch-Dest", "document")
+	req.Header.Set("Sec-Fetch-Mode", "navigate")
+	req.Header.Set("Sec-Fetch-Site", "none")
+	req.Header.Set("Sec-Fetch-User", "?1")
+	req.Header.Set("Upgrade-Insecure-Requests", "1")
+
+	if err := outbound.Wait(ctx, "token-direct"); err != nil {
+		return "", err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error making request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("received non-200 status code: %d", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading response: %v", err)
+	}
+
+	content := string(body)
+	// log.Printf("HTML Content Length: %d", len(content)) // Optional: Log content length for debugging
+
+	var token string
+
+	// --- Strategy 1: Look for specific script tag content ---
+	scriptRegex := regexp.MustCompile(`(?s)<script id="__NEXT_DATA__" type="application/json">(.*?)</script>`)
+	match := scriptRegex.FindStringSubmatch(content)
+	if len(match) > 1 {
+		jsonData := match[1]
+		// Try parsing the JSON to find the token - this is more robust if structure is known
+		var nextData map[string]interface{}
+		if json.Unmarshal([]byte(jsonData), &nextData) == nil {
+			// Navigate through the JSON structure if the path to the token is known
+			// Example: if props, ok := nextData["props"].(map[string]interface{}); ok { ... }
+			// For now, just search within the raw JSON string
+			jsonStr := string(jsonData)
+			patterns := []string{`"x-mas":"(.*?)"`} // More specific pattern within JSON
+			for _, pattern := range patterns {
+				r := regexp.MustCompile(pattern)
+				tokenMatch := r.FindStringSubmatch(jsonStr)
+				if len(tokenMatch) > 1 && len(tokenMatch[1]) > 20 {
+					token = tokenMatch[1]
+					log.Println("Found token via __NEXT_DATA__ JSON search.")
+					break
+				}
+			}
+		}
+	}
+
+	// --- Strategy 2: Regex for JWT patterns in the whole HTML ---
+	if token == "" {
+		log.Println("Token not found in __NEXT_DATA__, searching entire HTML for JWT patterns...")
+		jwtPattern := `eyJ[a-zA-Z0-9_-]{10,}\.eyJ[a-zA-Z0-9_-]{50,}\.[a-zA-Z0-9_-]+` // More specific JWT pattern
+		jwtRegex := regexp.MustCompile(jwtPattern)
+		matches := jwtRegex.FindAllString(content, -1)
+
+		if len(matches) > 0 {
+			// Often the longest JWT is the one needed
+			longestToken := ""
+			for _, match := range matches {
+				if len(match) > len(longestToken) {
+					longestToken = match
+				}
+			}
+			if len(longestToken) > 100 { // Basic validation
+				token = longestToken
+				log.Println("Found potential token via general JWT regex search.")
+			}
+		}
+	}
+
+	// --- Strategy 3: Original simple string search (fallback) ---
+	if token == "" {
+		log.Println("JWT regex failed, trying simple string search...")
+		patterns := []string{`"x-mas":"`, `'x-mas':'`, `"x-mas"\s*:\s*"`}
+		for _, pattern := range patterns {
+			idx := strings.Index(content, pattern)
+			if idx >= 0 {
+				tokenStart := idx + len(pattern)
+				quoteChar := pattern[len(pattern)-1:] // Get the quote character
+				tokenEnd := strings.Index(content[tokenStart:], quoteChar)
+
+				if tokenEnd > 0 {
+					potentialToken := content[tokenStart : tokenStart+tokenEnd]
+					if len(potentialToken) > 20 { // Basic validation
+						token = potentialToken
+						log.Println("Found token via simple string search.")
+						break
+					}
+				}
+			}
+		}
+	}
+
+	if token == "" {
+		// Optional: Save HTML for inspection if token not found
+		// ioutil.WriteFile("fotmob_debug.html", []byte(content), 0644)
+		return "", fmt.Errorf("token not found in HTML response using multiple strategies")
+	}
+
+	log.Printf("Found token: %s...", TruncateToken(token))
+
+	// Create headers file
+	headers := map[string]interface{}{
+		"x-mas":      token,
+		"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36",
+		"Accept":     "application/json, text/plain, */*",
+		"_timestamp": time.Now().Format(time.RFC3339),
+		"_scrapedAt": time.Now().Unix(),
+	}
+
+	// Save headers file (Corrected path)
+	headersFilePath := filepath.Join("responses", "currency_api_headers.json")
+	tokenFilePath := filepath.Join("responses", "x-mas-token.txt")
+
+	os.MkdirAll("responses", 0755)
+	headersJSON, _ := json.MarshalIndent(headers, "", "  ")
+	err = ioutil.WriteFile(headersFilePath, headersJSON, 0644)
+	if err != nil {
+		log.Printf("Warning: Failed to write headers file %s: %v", headersFilePath, err)
+		// Don't return error, just log it
+	}
+
+	// Also save token to separate file
+	err = ioutil.WriteFile(tokenFilePath, []byte(token), 0644)
+	if err != nil {
+		log.Printf("Warning: Failed to write token file %s: %v", tokenFilePath, err)
+		// Don't return error, just log it
+	}
+
+	return token, nil
+}
@@ -13,18 +13,19 @@ import (
 	"strings"
 	"time"
 
+	"github.com/MichaelBabushkin/sammy_po/pkg/outbound"
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
 )
 
 // FallbackWithSimpleHTTP gets the token using a simple HTTP request (kept as fallback)
-func FallbackWithSimpleHTTP() (string, error) {
+func FallbackWithSimpleHTTP(ctx context.Context) (string, error) {
 	fmt.Println("Using simple HTTP request to get token...")
 
 	client := &http.Client{
 		Timeout: 15 * time.Second,
 	}
-	req, err := http.NewRequest("GET", "https://www.fotmob.com", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://www.fotmob.com", nil)
 	if err != nil {
 		return "", fmt.Errorf("error creating request: %v", err)
 	}
@@ -36,6 +37,10 @@ func FallbackWithSimpleHTTP() (string, error) {
 	req.Header.Set("Cache-Control", "no-cache")
 	req.Header.Set("Pragma", "no-cache")
 
+	if err := outbound.Wait(ctx, "token-fallback"); err != nil {
+		return "", err
+	}
+
 	resp, err := client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("error making request: %v", err)
@@ -99,11 +104,41 @@ func FallbackWithSimpleHTTP() (string, error) {
 	return token, nil
 }
 
-// RunTokenScraper runs the browser automation to get the Fotmob token
-func RunTokenScraper(silent bool) (string, error) {
+// RunTokenScraper runs the browser automation to get the Fotmob token.
+// Cancelling ctx stops the browser and skips the fallback.
+func RunTokenScraper(ctx context.Context, silent bool) (string, error) {
+	token, err := ScrapeWithBrowser(ctx, silent)
+	if err == nil {
+		return token, nil
+	}
+	if ctx.Err() != nil {
+		return "", err
+	}
+
+	// If browser automation failed or didn't find token, try fallback
+	if !silent {
+		fmt.Println("Browser automation didn't find token, trying fallback HTTP method...")
+	}
+	token, fallbackErr := FallbackWithSimpleHTTP(ctx)
+	if fallbackErr != nil {
+		return "", fmt.Errorf("%v, and fallback HTTP failed (%v)", err, fallbackErr)
+	}
+
+	return token, nil // Return token from fallback
+}
+
+// ScrapeWithBrowser captures the token from Fotmob's own API requests in
+// headless Chrome, without any fallback. The browser is shut down when ctx
+// ends.
+func ScrapeWithBrowser(ctx context.Context, silent bool) (string, error) {
 	if !silent {
 		fmt.Println("Starting browser automation...")
 	}
+	// The page load is counted as a single request against the budget
+	if err := outbound.Wait(ctx, "token-browser"); err != nil {
+		return "", err
+	}
+
 	// Create a context
 	opts := append(chromedp.DefaultExecAllocatorOptions[:],
 		chromedp.Flag("headless", true), // Run headless
@@ -114,10 +149,10 @@ func RunTokenScraper(silent bool) (string, error) {
 		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36"),
 	)
 
-	allocCtx, cancel := chromedp.NewExecAllocator(context.Background(), opts...)
+	allocCtx, cancel := chromedp.NewExecAllocator(ctx, opts...)
 	defer cancel()
 
-	ctx, cancel := chromedp.NewContext(allocCtx, chromedp.WithLogf(log.Printf))
+	ctx, cancel = chromedp.NewContext(allocCtx, chromedp.WithLogf(log.Printf))
 	defer cancel()
 
 	// Set a timeout for the entire operation
@@ -142,7 +177,7 @@ func RunTokenScraper(silent bool) (string, error) {
 					if str, ok := xmas.(string); ok && len(str) > 100 { // Check length
 						xmasToken = str
 						if !silent {
-							fmt.Printf("Found x-mas token via network listener: %s...\n", truncateToken(xmasToken))
+							fmt.Printf("Found x-mas token via network listener: %s...\n", TruncateToken(xmasToken))
 						}
 
 						// Capture all headers from this specific request
@@ -191,20 +226,10 @@ func RunTokenScraper(silent bool) (string, error) {
 		return xmasToken, nil
 	}
 
-	// If browser automation failed or didn't find token, try fallback
-	if !silent {
-		fmt.Println("Browser automation didn't find token, trying fallback HTTP method...")
-	}
-	token, fallbackErr := FallbackWithSimpleHTTP()
-	if fallbackErr != nil {
-		// Combine errors if both methods failed
-		if err != nil {
-			return "", fmt.Errorf("browser automation failed (%v) and fallback HTTP failed (%v)", err, fallbackErr)
-		}
-		return "", fmt.Errorf("browser automation succeeded but found no token, and fallback HTTP failed (%v)", fallbackErr)
+	if err != nil {
+		return "", fmt.Errorf("browser automation failed (%v)", err)
 	}
-
-	return token, nil // Return token from fallback
+	return "", fmt.Errorf("browser automation succeeded but found no token")
 }
 
 // saveTokenAndHeaders saves the token and constructs headers map
@@ -243,8 +268,8 @@ func saveTokenAndHeadersFromJSON(token string, headersJSON []byte) {
 	}
 }
 
-// Helper function to truncate token for logging
-func truncateToken(token string) string {
+// TruncateToken shortens a token for logging
+func TruncateToken(token string) string {
 	if len(token) > 30 {
 		return token[:30] + "..."
 	}
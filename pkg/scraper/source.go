@@ -0,0 +1,212 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenSource is one strategy for obtaining a fresh x-mas token. Every
+// source saves the token it finds to the responses directory.
+type TokenSource interface {
+	Name() string
+	Token(ctx context.Context) (string, error)
+}
+
+type tokenSourceFunc struct {
+	name  string
+	fetch func(ctx context.Context) (string, error)
+}
+
+func (s tokenSourceFunc) Name() string                              { return s.name }
+func (s tokenSourceFunc) Token(ctx context.Context) (string, error) { return s.fetch(ctx) }
+
+// Built-in token sources, in the order refreshes try them
+var (
+	BrowserSource = tokenSourceFunc{"chromedp", func(ctx context.Context) (string, error) { return ScrapeWithBrowser(ctx, true) }}
+	HTTPSource    = tokenSourceFunc{"http-fallback", FallbackWithSimpleHTTP}
+	DirectSource  = tokenSourceFunc{"direct-http", GetTokenDirectHTTP}
+)
+
+// Sources returns the built-in token sources in refresh order
+func Sources() []TokenSource {
+	return []TokenSource{BrowserSource, HTTPSource, DirectSource}
+}
+
+// SourceByName looks up a built-in token source
+func SourceByName(name string) (TokenSource, bool) {
+	for _, source := range Sources() {
+		if source.Name() == name {
+			return source, true
+		}
+	}
+	return nil, false
+}
+
+// RefreshAttempt records one try of one token source
+type RefreshAttempt struct {
+	Source    string    `json:"source"`
+	Trigger   string    `json:"trigger"`
+	StartedAt time.Time `json:"startedAt"`
+	Duration  string    `json:"duration"`
+	Success   bool      `json:"success"`
+	Error     string    `json:"error,omitempty"`
+	Token     string    `json:"tokenPreview,omitempty"`
+}
+
+// SourceStats counts outcomes for one token source
+type SourceStats struct {
+	Successes   int        `json:"successes"`
+	Failures    int        `json:"failures"`
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+	LastFailure *time.Time `json:"lastFailure,omitempty"`
+}
+
+const refreshHistoryLimit = 50
+
+var refreshState = struct {
+	sync.Mutex
+	history       []RefreshAttempt
+	stats         map[string]*SourceStats
+	forced        string
+	currentSource string
+}{stats: make(map[string]*SourceStats)}
+
+// ForceSource makes refreshes use only the named source. An empty name
+// restores the default order.
+func ForceSource(name string) error {
+	if name != "" {
+		if _, ok := SourceByName(name); !ok {
+			return fmt.Errorf("unknown token source %q", name)
+		}
+	}
+
+	refreshState.Lock()
+	refreshState.forced = name
+	refreshState.Unlock()
+
+	if name == "" {
+		log.Println("Token refreshes use the default source order")
+	} else {
+		log.Printf("Token refreshes forced to source %q", name)
+	}
+	return nil
+}
+
+// ForcedSource returns the source refreshes are pinned to, if any
+func ForcedSource() string {
+	refreshState.Lock()
+	defer refreshState.Unlock()
+	return refreshState.forced
+}
+
+// CurrentSource returns the source that produced the token in use, or an
+// empty string if it was stored before this process started
+func CurrentSource() string {
+	refreshState.Lock()
+	defer refreshState.Unlock()
+	return refreshState.currentSource
+}
+
+// RefreshHistory returns recent attempts, newest first
+func RefreshHistory() []RefreshAttempt {
+	refreshState.Lock()
+	defer refreshState.Unlock()
+
+	history := make([]RefreshAttempt, len(refreshState.history))
+	for i, attempt := range refreshState.history {
+		history[len(history)-1-i] = attempt
+	}
+	return history
+}
+
+// RefreshStats returns per-source outcome counts
+func RefreshStats() map[string]SourceStats {
+	refreshState.Lock()
+	defer refreshState.Unlock()
+
+	stats := make(map[string]SourceStats, len(refreshState.stats))
+	for name, s := range refreshState.stats {
+		stats[name] = *s
+	}
+	return stats
+}
+
+// Refresh tries the token sources in order (or only the forced one) and
+// returns the first token found with the name of the source that found it.
+// trigger describes why the refresh happened and is kept in the history.
+// Once ctx ends no further sources are tried.
+func Refresh(ctx context.Context, trigger string) (string, string, error) {
+	sources := Sources()
+	if forced := ForcedSource(); forced != "" {
+		source, _ := SourceByName(forced)
+		sources = []TokenSource{source}
+	}
+
+	var failures []string
+	for _, source := range sources {
+		if err := ctx.Err(); err != nil {
+			failures = append(failures, err.Error())
+			break
+		}
+		log.Printf("Attempting token refresh via %s (%s)...", source.Name(), trigger)
+
+		start := time.Now()
+		token, err := source.Token(ctx)
+		if err == nil && len(token) < 20 {
+			err = fmt.Errorf("invalid token %q", token)
+		}
+		recordAttempt(source.Name(), trigger, start, token, err)
+
+		if err == nil {
+			log.Printf("Token refreshed via %s: %s", source.Name(), TruncateToken(token))
+			return token, source.Name(), nil
+		}
+
+		log.Printf("Token source %s failed: %v", source.Name(), err)
+		failures = append(failures, fmt.Sprintf("%s: %v", source.Name(), err))
+	}
+
+	return "", "", fmt.Errorf("all token sources failed (%s)", strings.Join(failures, "; "))
+}
+
+func recordAttempt(source, trigger string, start time.Time, token string, err error) {
+	now := time.Now()
+	attempt := RefreshAttempt{
+		Source:    source,
+		Trigger:   trigger,
+		StartedAt: start,
+		Duration:  now.Sub(start).Round(time.Millisecond).String(),
+		Success:   err == nil,
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	} else {
+		attempt.Token = TruncateToken(token)
+	}
+
+	refreshState.Lock()
+	defer refreshState.Unlock()
+
+	refreshState.history = append(refreshState.history, attempt)
+	if len(refreshState.history) > refreshHistoryLimit {
+		refreshState.history = refreshState.history[len(refreshState.history)-refreshHistoryLimit:]
+	}
+
+	stats, ok := refreshState.stats[source]
+	if !ok {
+		stats = &SourceStats{}
+		refreshState.stats[source] = stats
+	}
+	if err != nil {
+		stats.Failures++
+		stats.LastFailure = &now
+	} else {
+		stats.Successes++
+		stats.LastSuccess = &now
+		refreshState.currentSource = source
+	}
+}
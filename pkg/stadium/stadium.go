@@ -0,0 +1,368 @@
+// Package stadium holds the editable stadium profiles: names and addresses
+// in English and Hebrew, capacity, gates, stands, accessibility, parking,
+// opening hours, location and photos. Profiles are kept in a JSON file with
+// every edit as a numbered version.
+package stadium
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+)
+
+// SammyOfer is the slug of the stadium this app is about
+const SammyOfer = "sammyofer"
+
+// Coordinates are a WGS 84 position
+type Coordinates struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// Gate is an entrance to the stadium
+type Gate struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	NameHe string `json:"nameHe,omitempty"`
+	// Stands lists the IDs of the stands the gate leads to
+	Stands      []string     `json:"stands"`
+	Accessible  bool         `json:"accessible"`
+	Coordinates *Coordinates `json:"coordinates,omitempty"`
+}
+
+// Stand is a stand and its seating sections
+type Stand struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	NameHe   string   `json:"nameHe,omitempty"`
+	Sections []string `json:"sections"`
+	Capacity int      `json:"capacity,omitempty"`
+}
+
+// FacilityTypes are the kinds of accessibility facility
+var FacilityTypes = []string{"wheelchair-seating", "accessible-entrance", "accessible-toilets", "accessible-parking", "elevator", "hearing-loop", "sensory-room", "other"}
+
+// Facility is an accessibility facility, optionally at a gate or stand
+type Facility struct {
+	Type          string `json:"type"`
+	Description   string `json:"description"`
+	DescriptionHe string `json:"descriptionHe,omitempty"`
+	Gate          string `json:"gate,omitempty"`
+	Stand         string `json:"stand,omitempty"`
+}
+
+// ParkingLot is a car park serving the stadium
+type ParkingLot struct {
+	Name             string       `json:"name"`
+	NameHe           string       `json:"nameHe,omitempty"`
+	Spaces           int          `json:"spaces,omitempty"`
+	AccessibleSpaces int          `json:"accessibleSpaces,omitempty"`
+	Paid             bool         `json:"paid"`
+	Notes            string       `json:"notes,omitempty"`
+	NotesHe          string       `json:"notesHe,omitempty"`
+	Coordinates      *Coordinates `json:"coordinates,omitempty"`
+}
+
+// Weekdays are the day names OpeningHours uses
+var Weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// OpeningHours are when something at the stadium, such as the box office
+// or the club shop, is open. Times are Israel local, as HH:MM.
+type OpeningHours struct {
+	Label   string   `json:"label"`
+	LabelHe string   `json:"labelHe,omitempty"`
+	Days    []string `json:"days"`
+	Opens   string   `json:"opens"`
+	Closes  string   `json:"closes"`
+}
+
+// Photo is a picture of the stadium
+type Photo struct {
+	URL       string `json:"url"`
+	Caption   string `json:"caption,omitempty"`
+	CaptionHe string `json:"captionHe,omitempty"`
+	Credit    string `json:"credit,omitempty"`
+}
+
+// Profile is everything we know about a stadium. Text fields have a Hebrew
+// variant that falls back to the English one when empty.
+type Profile struct {
+	Slug          string `json:"slug"`
+	Name          string `json:"name"`
+	NameHe        string `json:"nameHe,omitempty"`
+	City          string `json:"city"`
+	CityHe        string `json:"cityHe,omitempty"`
+	Country       string `json:"country"`
+	CountryHe     string `json:"countryHe,omitempty"`
+	Address       string `json:"address"`
+	AddressHe     string `json:"addressHe,omitempty"`
+	Description   string `json:"description"`
+	DescriptionHe string `json:"descriptionHe,omitempty"`
+	Capacity      int    `json:"capacity"`
+	// ImageURL is the main picture; Photos holds the rest
+	ImageURL    string       `json:"imageUrl"`
+	Coordinates *Coordinates `json:"coordinates,omitempty"`
+
+	Gates         []Gate         `json:"gates"`
+	Stands        []Stand        `json:"stands"`
+	Accessibility []Facility     `json:"accessibility"`
+	Parking       []ParkingLot   `json:"parking"`
+	OpeningHours  []OpeningHours `json:"openingHours"`
+	Photos        []Photo        `json:"photos"`
+
+	// Version counts the edits; a write naming an older one is refused
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	UpdatedBy string    `json:"updatedBy,omitempty"`
+}
+
+// Defaults are the profiles a new store starts with
+var Defaults = []Profile{{
+	Slug:          SammyOfer,
+	Name:          teams.SammyOferStadium,
+	NameHe:        "אצטדיון סמי עופר",
+	City:          "Haifa",
+	CityHe:        "חיפה",
+	Country:       "Israel",
+	CountryHe:     "ישראל",
+	Address:       "32 Haim Weizmann St., Haifa, Israel",
+	AddressHe:     "רחוב חיים ויצמן 32, חיפה, ישראל",
+	Description:   "Sammy Ofer Stadium is a football stadium in Haifa, Israel. It serves as a venue for home matches of both Maccabi Haifa and Hapoel Haifa football clubs. The stadium is named after shipping magnate and philanthropist Sammy Ofer, who donated $20 million to help build the stadium.",
+	DescriptionHe: "אצטדיון סמי עופר הוא אצטדיון כדורגל בחיפה. הוא משמש כמגרש הבית של מכבי חיפה ושל הפועל חיפה. האצטדיון נקרא על שם איל הספנות והנדבן סמי עופר, שתרם 20 מיליון דולר להקמתו.",
+	Capacity:      30858,
+	ImageURL:      "https://stadiumdb.com/pictures/stadiums/isr/sammy_ofer_stadium/sammy_ofer_stadium21.jpg",
+	Coordinates:   &Coordinates{Lat: 32.7831, Lng: 34.9654},
+}}
+
+// Info is a profile in one language, with the clubs that play there
+type Info struct {
+	Profile
+	Teams []string     `json:"teams"`
+	Clubs []teams.Team `json:"clubs"`
+}
+
+// Info returns the profile translated into locale
+func (p Profile) Info(locale i18n.Locale) Info {
+	pick := func(en, he string) string {
+		if locale == i18n.Hebrew && he != "" {
+			return he
+		}
+		return en
+	}
+
+	info := Info{Profile: p.clone()}
+	l := &info.Profile
+	l.Name, l.NameHe = pick(p.Name, p.NameHe), ""
+	l.City, l.CityHe = pick(p.City, p.CityHe), ""
+	l.Country, l.CountryHe = pick(p.Country, p.CountryHe), ""
+	l.Address, l.AddressHe = pick(p.Address, p.AddressHe), ""
+	l.Description, l.DescriptionHe = pick(p.Description, p.DescriptionHe), ""
+	l.UpdatedBy = ""
+	for i, gate := range l.Gates {
+		l.Gates[i].Name, l.Gates[i].NameHe = pick(gate.Name, gate.NameHe), ""
+	}
+	for i, stand := range l.Stands {
+		l.Stands[i].Name, l.Stands[i].NameHe = pick(stand.Name, stand.NameHe), ""
+	}
+	for i, facility := range l.Accessibility {
+		l.Accessibility[i].Description, l.Accessibility[i].DescriptionHe = pick(facility.Description, facility.DescriptionHe), ""
+	}
+	for i, lot := range l.Parking {
+		l.Parking[i].Name, l.Parking[i].NameHe = pick(lot.Name, lot.NameHe), ""
+		l.Parking[i].Notes, l.Parking[i].NotesHe = pick(lot.Notes, lot.NotesHe), ""
+	}
+	for i, hours := range l.OpeningHours {
+		l.OpeningHours[i].Label, l.OpeningHours[i].LabelHe = pick(hours.Label, hours.LabelHe), ""
+	}
+	for i, photo := range l.Photos {
+		l.Photos[i].Caption, l.Photos[i].CaptionHe = pick(photo.Caption, photo.CaptionHe), ""
+	}
+
+	info.Clubs = teams.Default.AtVenue(teams.CanonicalVenue(p.Name))
+	info.Teams = make([]string, 0, len(info.Clubs))
+	for _, club := range info.Clubs {
+		info.Teams = append(info.Teams, i18n.TeamName(locale, club))
+	}
+	return info
+}
+
+// clone copies the profile so its slices can be changed independently
+func (p Profile) clone() Profile {
+	c := p
+	if p.Coordinates != nil {
+		coordinates := *p.Coordinates
+		c.Coordinates = &coordinates
+	}
+	c.Gates = append([]Gate{}, p.Gates...)
+	for i, gate := range c.Gates {
+		c.Gates[i].Stands = append([]string{}, gate.Stands...)
+		if gate.Coordinates != nil {
+			coordinates := *gate.Coordinates
+			c.Gates[i].Coordinates = &coordinates
+		}
+	}
+	c.Stands = append([]Stand{}, p.Stands...)
+	for i, stand := range c.Stands {
+		c.Stands[i].Sections = append([]string{}, stand.Sections...)
+	}
+	c.Accessibility = append([]Facility{}, p.Accessibility...)
+	c.Parking = append([]ParkingLot{}, p.Parking...)
+	for i, lot := range c.Parking {
+		if lot.Coordinates != nil {
+			coordinates := *lot.Coordinates
+			c.Parking[i].Coordinates = &coordinates
+		}
+	}
+	c.OpeningHours = append([]OpeningHours{}, p.OpeningHours...)
+	for i, hours := range c.OpeningHours {
+		c.OpeningHours[i].Days = append([]string{}, hours.Days...)
+	}
+	c.Photos = append([]Photo{}, p.Photos...)
+	return c
+}
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,39}$`)
+
+// normalize trims text and fills in empty lists
+func (p *Profile) normalize() {
+	for _, s := range []*string{&p.Slug, &p.Name, &p.NameHe, &p.City, &p.CityHe, &p.Country, &p.CountryHe,
+		&p.Address, &p.AddressHe, &p.Description, &p.DescriptionHe, &p.ImageURL} {
+		*s = strings.TrimSpace(*s)
+	}
+	p.Slug = strings.ToLower(p.Slug)
+	*p = p.clone()
+	for i := range p.OpeningHours {
+		for j, day := range p.OpeningHours[i].Days {
+			p.OpeningHours[i].Days[j] = strings.ToLower(strings.TrimSpace(day))
+		}
+	}
+}
+
+func (p Profile) validate() error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalid, fmt.Sprintf(format, args...))
+	}
+
+	if !slugPattern.MatchString(p.Slug) {
+		return invalid("slug must be lowercase letters, digits and dashes")
+	}
+	if p.Name == "" {
+		return invalid("name is required")
+	}
+	if p.Capacity < 0 {
+		return invalid("capacity can't be negative")
+	}
+	if err := validCoordinates(p.Coordinates); err != nil {
+		return invalid("coordinates: %v", err)
+	}
+	if p.ImageURL != "" && !validURL(p.ImageURL) {
+		return invalid("imageUrl must be an http or https URL")
+	}
+
+	stands := make(map[string]bool)
+	for i, stand := range p.Stands {
+		if stand.ID == "" || stand.Name == "" {
+			return invalid("stands[%d] needs an id and a name", i)
+		}
+		if stands[stand.ID] {
+			return invalid("stand id %q is used twice", stand.ID)
+		}
+		if stand.Capacity < 0 {
+			return invalid("stands[%d] capacity can't be negative", i)
+		}
+		stands[stand.ID] = true
+	}
+	gates := make(map[string]bool)
+	for i, gate := range p.Gates {
+		if gate.ID == "" || gate.Name == "" {
+			return invalid("gates[%d] needs an id and a name", i)
+		}
+		if gates[gate.ID] {
+			return invalid("gate id %q is used twice", gate.ID)
+		}
+		gates[gate.ID] = true
+		for _, stand := range gate.Stands {
+			if !stands[stand] {
+				return invalid("gate %q leads to unknown stand %q", gate.ID, stand)
+			}
+		}
+		if err := validCoordinates(gate.Coordinates); err != nil {
+			return invalid("gate %q coordinates: %v", gate.ID, err)
+		}
+	}
+	for i, facility := range p.Accessibility {
+		if !contains(FacilityTypes, facility.Type) {
+			return invalid("accessibility[%d] type must be one of %s", i, strings.Join(FacilityTypes, ", "))
+		}
+		if facility.Gate != "" && !gates[facility.Gate] {
+			return invalid("accessibility[%d] is at unknown gate %q", i, facility.Gate)
+		}
+		if facility.Stand != "" && !stands[facility.Stand] {
+			return invalid("accessibility[%d] is in unknown stand %q", i, facility.Stand)
+		}
+	}
+	for i, lot := range p.Parking {
+		if lot.Name == "" {
+			return invalid("parking[%d] needs a name", i)
+		}
+		if lot.Spaces < 0 || lot.AccessibleSpaces < 0 {
+			return invalid("parking[%d] spaces can't be negative", i)
+		}
+		if err := validCoordinates(lot.Coordinates); err != nil {
+			return invalid("parking[%d] coordinates: %v", i, err)
+		}
+	}
+	for i, hours := range p.OpeningHours {
+		if hours.Label == "" || len(hours.Days) == 0 {
+			return invalid("openingHours[%d] needs a label and days", i)
+		}
+		for _, day := range hours.Days {
+			if !contains(Weekdays, day) {
+				return invalid("openingHours[%d] day %q must be one of %s", i, day, strings.Join(Weekdays, ", "))
+			}
+		}
+		opens, err1 := time.Parse("15:04", hours.Opens)
+		closes, err2 := time.Parse("15:04", hours.Closes)
+		if err1 != nil || err2 != nil {
+			return invalid("openingHours[%d] opens and closes must be HH:MM", i)
+		}
+		if !closes.After(opens) {
+			return invalid("openingHours[%d] must close after it opens", i)
+		}
+	}
+	for i, photo := range p.Photos {
+		if !validURL(photo.URL) {
+			return invalid("photos[%d] url must be an http or https URL", i)
+		}
+	}
+	return nil
+}
+
+func validCoordinates(c *Coordinates) error {
+	if c == nil {
+		return nil
+	}
+	if c.Lat < -90 || c.Lat > 90 || c.Lng < -180 || c.Lng > 180 {
+		return fmt.Errorf("lat must be within ±90 and lng within ±180")
+	}
+	return nil
+}
+
+func validURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
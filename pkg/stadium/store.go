@@ -0,0 +1,387 @@
+package stadium
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned for an unknown stadium or version
+	ErrNotFound = errors.New("stadium not found")
+	// ErrExists is returned when creating a stadium whose slug is taken
+	ErrExists = errors.New("a stadium with this slug already exists")
+	// ErrConflict is returned for a write based on an outdated version
+	ErrConflict = errors.New("the stadium was changed since that version")
+	// ErrProtected is returned when deleting the Sammy Ofer profile, which
+	// the rest of the app depends on
+	ErrProtected = errors.New("the Sammy Ofer profile can be edited but not deleted")
+	// ErrInvalid wraps validation failures
+	ErrInvalid = errors.New("invalid stadium profile")
+)
+
+// Version actions
+const (
+	ActionSeed    = "seed"
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+)
+
+// Version is one edit of a stadium, with the profile as it was afterwards;
+// a deletion has no profile
+type Version struct {
+	Slug    string    `json:"slug"`
+	Version int       `json:"version"`
+	Time    time.Time `json:"time"`
+	Actor   string    `json:"actor"`
+	Action  string    `json:"action"`
+	// Changed lists the top-level fields that differ from the version
+	// before
+	Changed []string `json:"changed,omitempty"`
+	Profile *Profile `json:"profile,omitempty"`
+}
+
+// Store keeps the stadium profiles and their versions in a JSON file
+type Store struct {
+	path string
+
+	mu       sync.Mutex
+	profiles map[string]Profile
+	versions []Version
+	// loadErr makes the store refuse writes that would replace a file it
+	// couldn't read
+	loadErr error
+}
+
+type storeFile struct {
+	Profiles []Profile `json:"profiles"`
+	Versions []Version `json:"versions"`
+}
+
+// Open loads the store at path; a missing file starts from Defaults, saved
+// on the first edit. If the file can't be read the store is returned with
+// the defaults along with the error, and refuses changes until the file is
+// fixed.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, profiles: make(map[string]Profile), versions: []Version{}}
+
+	data, err := os.ReadFile(path)
+	if err != nil || len(data) == 0 {
+		s.seed()
+		if err != nil && !os.IsNotExist(err) {
+			s.loadErr = err
+			return s, err
+		}
+		return s, nil
+	}
+
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		s.seed()
+		s.loadErr = fmt.Errorf("stadium file %s is corrupt: %v", path, err)
+		return s, s.loadErr
+	}
+	for _, p := range file.Profiles {
+		s.profiles[p.Slug] = p
+	}
+	if file.Versions != nil {
+		s.versions = file.Versions
+	}
+	log.Printf("Loaded %d stadium profiles from %s", len(s.profiles), path)
+	return s, nil
+}
+
+// seed fills the store with Defaults as their first versions
+func (s *Store) seed() {
+	for _, p := range Defaults {
+		p.normalize()
+		p.Version = 1
+		snapshot := p.clone()
+		s.profiles[p.Slug] = p
+		s.versions = append(s.versions, Version{Slug: p.Slug, Version: 1, Actor: "system", Action: ActionSeed, Profile: &snapshot})
+	}
+}
+
+// List returns the profiles ordered by slug
+func (s *Store) List() []Profile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]Profile, 0, len(s.profiles))
+	for _, p := range s.profiles {
+		list = append(list, p.clone())
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Slug < list[j].Slug })
+	return list
+}
+
+// Get returns the current profile of a stadium
+func (s *Store) Get(slug string) (Profile, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.profiles[slug]
+	return p.clone(), ok
+}
+
+// Versions returns a stadium's versions newest first
+func (s *Store) Versions(slug string) ([]Version, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions := []Version{}
+	for i := len(s.versions) - 1; i >= 0; i-- {
+		if s.versions[i].Slug == slug {
+			versions = append(versions, s.versions[i])
+		}
+	}
+	return versions, len(versions) > 0
+}
+
+// Version returns one version of a stadium
+func (s *Store) Version(slug string, version int) (Version, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, v := range s.versions {
+		if v.Slug == slug && v.Version == version {
+			return v, true
+		}
+	}
+	return Version{}, false
+}
+
+// Create adds a stadium
+func (s *Store) Create(p Profile, actor string) (Profile, error) {
+	p.normalize()
+	if err := p.validate(); err != nil {
+		return Profile{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.profiles[p.Slug]; ok {
+		return Profile{}, ErrExists
+	}
+	return s.change(p, actor, ActionCreate)
+}
+
+// Update replaces a stadium's profile. A non-zero p.Version must be the
+// current one, so edits made from an outdated copy are refused.
+func (s *Store) Update(slug string, p Profile, actor string) (Profile, error) {
+	p.Slug = slug
+	p.normalize()
+	if err := p.validate(); err != nil {
+		return Profile{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.profiles[slug]
+	if !ok {
+		return Profile{}, ErrNotFound
+	}
+	if p.Version != 0 && p.Version != current.Version {
+		return Profile{}, ErrConflict
+	}
+	return s.change(p, actor, ActionUpdate)
+}
+
+// Patch applies a JSON merge patch (RFC 7396) to a stadium's profile. A
+// "version" in the patch must be the current one; without it the patch is
+// refused if the profile changed after it was read for merging.
+func (s *Store) Patch(slug string, patch []byte, actor string) (Profile, error) {
+	current, ok := s.Get(slug)
+	if !ok {
+		return Profile{}, ErrNotFound
+	}
+
+	var changes map[string]interface{}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return Profile{}, fmt.Errorf("%w: the patch must be a JSON object: %v", ErrInvalid, err)
+	}
+	base, err := json.Marshal(current)
+	if err != nil {
+		return Profile{}, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(base, &doc); err != nil {
+		return Profile{}, err
+	}
+	merged, err := json.Marshal(mergePatch(doc, changes))
+	if err != nil {
+		return Profile{}, err
+	}
+
+	var p Profile
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&p); err != nil {
+		return Profile{}, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if _, ok := changes["version"]; !ok {
+		p.Version = current.Version
+	}
+	return s.Update(slug, p, actor)
+}
+
+// mergePatch applies patch to doc as RFC 7396 describes
+func mergePatch(doc, patch map[string]interface{}) map[string]interface{} {
+	for key, value := range patch {
+		if value == nil {
+			delete(doc, key)
+			continue
+		}
+		if patchObject, ok := value.(map[string]interface{}); ok {
+			docObject, _ := doc[key].(map[string]interface{})
+			if docObject == nil {
+				docObject = make(map[string]interface{})
+			}
+			doc[key] = mergePatch(docObject, patchObject)
+			continue
+		}
+		doc[key] = value
+	}
+	return doc
+}
+
+// Delete removes a stadium; its versions are kept
+func (s *Store) Delete(slug string, actor string) error {
+	if slug == SammyOfer {
+		return ErrProtected
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.profiles[slug]; !ok {
+		return ErrNotFound
+	}
+	_, err := s.change(Profile{Slug: slug}, actor, ActionDelete)
+	return err
+}
+
+// Restore makes an earlier version of a stadium current again, as a new
+// version
+func (s *Store) Restore(slug string, version int, actor string) (Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, v := range s.versions {
+		if v.Slug == slug && v.Version == version {
+			if v.Profile == nil {
+				return Profile{}, fmt.Errorf("%w: version %d is a deletion", ErrInvalid, version)
+			}
+			return s.change(v.Profile.clone(), actor, ActionRestore)
+		}
+	}
+	return Profile{}, ErrNotFound
+}
+
+// change makes p the stadium's profile as a new version, or removes it for
+// a deletion, and saves the store, undoing both if the save fails. s.mu
+// must be held.
+func (s *Store) change(p Profile, actor, action string) (Profile, error) {
+	if s.loadErr != nil {
+		return Profile{}, s.loadErr
+	}
+
+	previous, existed := s.profiles[p.Slug]
+	number := 1
+	for _, v := range s.versions {
+		if v.Slug == p.Slug && v.Version >= number {
+			number = v.Version + 1
+		}
+	}
+
+	now := time.Now().UTC()
+	version := Version{Slug: p.Slug, Version: number, Time: now, Actor: actor, Action: action}
+	if action == ActionDelete {
+		delete(s.profiles, p.Slug)
+	} else {
+		p.Version, p.UpdatedAt, p.UpdatedBy = number, now, actor
+		snapshot := p.clone()
+		version.Profile = &snapshot
+		version.Changed = changedFields(previous, p, existed)
+		s.profiles[p.Slug] = p
+	}
+	s.versions = append(s.versions, version)
+
+	if err := s.save(); err != nil {
+		if existed {
+			s.profiles[p.Slug] = previous
+		} else {
+			delete(s.profiles, p.Slug)
+		}
+		s.versions = s.versions[:len(s.versions)-1]
+		return Profile{}, err
+	}
+	log.Printf("Stadium %s %s by %s (version %d)", p.Slug, action, actor, number)
+	return p.clone(), nil
+}
+
+// changedFields lists the top-level JSON fields of after that differ from
+// before, leaving out the bookkeeping ones
+func changedFields(before, after Profile, existed bool) []string {
+	if !existed {
+		return nil
+	}
+	toMap := func(p Profile) map[string]interface{} {
+		data, _ := json.Marshal(p)
+		var m map[string]interface{}
+		json.Unmarshal(data, &m)
+		delete(m, "version")
+		delete(m, "updatedAt")
+		delete(m, "updatedBy")
+		return m
+	}
+	a, b := toMap(before), toMap(after)
+
+	var changed []string
+	for key := range b {
+		if !reflect.DeepEqual(a[key], b[key]) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range a {
+		if _, ok := b[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// save writes the store atomically. s.mu must be held.
+func (s *Store) save() error {
+	file := storeFile{Profiles: make([]Profile, 0, len(s.profiles)), Versions: s.versions}
+	for _, p := range s.profiles {
+		file.Profiles = append(file.Profiles, p)
+	}
+	sort.Slice(file.Profiles, func(i, j int) bool { return file.Profiles[i].Slug < file.Profiles[j].Slug })
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	os.MkdirAll(filepath.Dir(s.path), 0755)
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to store stadium profiles: %v", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to store stadium profiles: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package teams
+
+// Venue names used by the seeded teams
+const (
+	SammyOferStadium    = "Sammy Ofer Stadium"
+	BloomfieldStadium   = "Bloomfield Stadium"
+	TurnerStadium       = "Turner Stadium"
+	TeddyStadium        = "Teddy Stadium"
+	NetanyaStadium      = "Netanya Stadium"
+	DohaStadium         = "Doha Stadium"
+	YudAlefStadium      = "Yud-Alef Stadium"
+	GreenStadium        = "Green Stadium"
+	HaMoshavaStadium    = "HaMoshava Stadium"
+	KiryatShmonaStadium = "Kiryat Shmona Municipal Stadium"
+)
+
+// Default is the registry of Israeli clubs used across the app, seeded with
+// their Fotmob team IDs. When Fotmob reports a club by name under another
+// ID, the new ID replaces the seeded one.
+var Default = NewRegistry([]Team{
+	{
+		ID:          8592,
+		Name:        "Maccabi Haifa",
+		NameHe:      "מכבי חיפה",
+		ShortName:   "M. Haifa",
+		ShortNameHe: "מכבי חיפה",
+		Aliases:     []string{"Maccabi Haifa FC", "Maccabi Haifa F.C.", "M Haifa"},
+		HomeVenue:   SammyOferStadium,
+	},
+	{
+		ID:          8597,
+		Name:        "Hapoel Haifa",
+		NameHe:      "הפועל חיפה",
+		ShortName:   "H. Haifa",
+		ShortNameHe: "הפועל חיפה",
+		Aliases:     []string{"Hapoel Haifa FC", "Hapoel Haifa F.C.", "H Haifa"},
+		HomeVenue:   SammyOferStadium,
+	},
+	{
+		ID:          8603,
+		Name:        "Maccabi Tel Aviv",
+		NameHe:      "מכבי תל אביב",
+		ShortName:   "M. Tel Aviv",
+		ShortNameHe: "מכבי ת\"א",
+		Aliases:     []string{"Maccabi Tel-Aviv", "Maccabi Tel Aviv FC", "M Tel Aviv"},
+		HomeVenue:   BloomfieldStadium,
+	},
+	{
+		ID:          8600,
+		Name:        "Hapoel Tel Aviv",
+		NameHe:      "הפועל תל אביב",
+		ShortName:   "H. Tel Aviv",
+		ShortNameHe: "הפועל ת\"א",
+		Aliases:     []string{"Hapoel Tel-Aviv", "Hapoel Tel Aviv FC", "H Tel Aviv"},
+		HomeVenue:   BloomfieldStadium,
+	},
+	{
+		ID:          8589,
+		Name:        "Hapoel Beer Sheva",
+		NameHe:      "הפועל באר שבע",
+		ShortName:   "H. Beer Sheva",
+		ShortNameHe: "הפועל ב\"ש",
+		Aliases:     []string{"Hapoel Be'er Sheva", "Hapoel Beer-Sheva", "H Beer Sheva"},
+		HomeVenue:   TurnerStadium,
+	},
+	{
+		ID:          8591,
+		Name:        "Beitar Jerusalem",
+		NameHe:      "בית\"ר ירושלים",
+		ShortName:   "Beitar",
+		ShortNameHe: "בית\"ר",
+		Aliases:     []string{"Beitar Jerusalem FC", "Betar Jerusalem"},
+		HomeVenue:   TeddyStadium,
+	},
+	{
+		ID:          165250,
+		Name:        "Hapoel Jerusalem",
+		NameHe:      "הפועל ירושלים",
+		ShortName:   "H. Jerusalem",
+		ShortNameHe: "הפועל י-ם",
+		Aliases:     []string{"Hapoel Jerusalem FC", "H Jerusalem"},
+		HomeVenue:   TeddyStadium,
+	},
+	{
+		ID:          8598,
+		Name:        "Maccabi Netanya",
+		NameHe:      "מכבי נתניה",
+		ShortName:   "M. Netanya",
+		ShortNameHe: "מכבי נתניה",
+		Aliases:     []string{"Maccabi Netanya FC", "M Netanya"},
+		HomeVenue:   NetanyaStadium,
+	},
+	{
+		ID:          8596,
+		Name:        "Bnei Sakhnin",
+		NameHe:      "בני סכנין",
+		ShortName:   "Sakhnin",
+		ShortNameHe: "סכנין",
+		Aliases:     []string{"Bnei Sachnin", "Ihud Bnei Sakhnin"},
+		HomeVenue:   DohaStadium,
+	},
+	{
+		ID:          8593,
+		Name:        "FC Ashdod",
+		NameHe:      "מ.ס. אשדוד",
+		ShortName:   "Ashdod",
+		ShortNameHe: "אשדוד",
+		Aliases:     []string{"MS Ashdod", "F.C. Ashdod", "Ashdod"},
+		HomeVenue:   YudAlefStadium,
+	},
+	{
+		ID:          489379,
+		Name:        "Hapoel Hadera",
+		NameHe:      "הפועל חדרה",
+		ShortName:   "H. Hadera",
+		ShortNameHe: "הפועל חדרה",
+		Aliases:     []string{"Hapoel Hadera FC", "H Hadera"},
+		HomeVenue:   NetanyaStadium,
+	},
+	{
+		ID:          1028906,
+		Name:        "Maccabi Bnei Reineh",
+		NameHe:      "מכבי בני ריינה",
+		ShortName:   "Bnei Reineh",
+		ShortNameHe: "בני ריינה",
+		Aliases:     []string{"Maccabi Bnei Raina", "Bnei Reineh"},
+		HomeVenue:   GreenStadium,
+	},
+	{
+		ID:          8595,
+		Name:        "Ironi Kiryat Shmona",
+		NameHe:      "עירוני קריית שמונה",
+		ShortName:   "Kiryat Shmona",
+		ShortNameHe: "ק. שמונה",
+		Aliases:     []string{"Hapoel Ironi Kiryat Shmona", "Kiryat Shmona"},
+		HomeVenue:   KiryatShmonaStadium,
+	},
+	{
+		ID:          8601,
+		Name:        "Hapoel Petah Tikva",
+		NameHe:      "הפועל פתח תקווה",
+		ShortName:   "H. Petah Tikva",
+		ShortNameHe: "הפועל פ\"ת",
+		Aliases:     []string{"Hapoel Petach Tikva", "H Petah Tikva"},
+		HomeVenue:   HaMoshavaStadium,
+	},
+	{
+		ID:          8599,
+		Name:        "Maccabi Petah Tikva",
+		NameHe:      "מכבי פתח תקווה",
+		ShortName:   "M. Petah Tikva",
+		ShortNameHe: "מכבי פ\"ת",
+		Aliases:     []string{"Maccabi Petach Tikva", "M Petah Tikva"},
+		HomeVenue:   HaMoshavaStadium,
+	},
+	{
+		ID:          188159,
+		Name:        "Ironi Tiberias",
+		NameHe:      "עירוני טבריה",
+		ShortName:   "Tiberias",
+		ShortNameHe: "טבריה",
+		Aliases:     []string{"Hapoel Ironi Tiberias", "Tiberias"},
+	},
+	{
+		ID:          8590,
+		Name:        "Bnei Yehuda",
+		NameHe:      "בני יהודה",
+		ShortName:   "Bnei Yehuda",
+		ShortNameHe: "בני יהודה",
+		Aliases:     []string{"Bnei Yehuda Tel Aviv"},
+		HomeVenue:   BloomfieldStadium,
+	},
+})
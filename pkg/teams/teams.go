@@ -0,0 +1,189 @@
+package teams
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Team is a club known to the app, with canonical names in English and Hebrew
+type Team struct {
+	ID          int      `json:"id"`
+	Name        string   `json:"name"`
+	NameHe      string   `json:"nameHe"`
+	ShortName   string   `json:"shortName"`
+	ShortNameHe string   `json:"shortNameHe"`
+	Aliases     []string `json:"aliases,omitempty"`
+	LogoURL     string   `json:"logoUrl,omitempty"`
+	HomeVenue   string   `json:"homeVenue,omitempty"`
+}
+
+// Registry indexes teams by Fotmob team ID and by normalised name/alias
+type Registry struct {
+	mu      sync.RWMutex
+	teams   []*Team
+	byID    map[int]*Team
+	byAlias map[string]*Team
+}
+
+// NewRegistry builds a registry from the given teams
+func NewRegistry(seed []Team) *Registry {
+	r := &Registry{
+		byID:    make(map[int]*Team),
+		byAlias: make(map[string]*Team),
+	}
+
+	for i := range seed {
+		team := seed[i]
+		r.teams = append(r.teams, &team)
+
+		if team.ID != 0 {
+			r.setID(&team, team.ID)
+		}
+		for _, name := range append([]string{team.Name, team.NameHe, team.ShortName, team.ShortNameHe}, team.Aliases...) {
+			if key := Normalize(name); key != "" {
+				r.byAlias[key] = &team
+			}
+		}
+	}
+
+	return r
+}
+
+// Lookup returns the team registered under a Fotmob team ID
+func (r *Registry) Lookup(id int) (Team, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if team, ok := r.byID[id]; ok {
+		return *team, true
+	}
+	return Team{}, false
+}
+
+// LookupName returns the team whose canonical name or alias equals name
+func (r *Registry) LookupName(name string) (Team, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if team, ok := r.byAlias[Normalize(name)]; ok {
+		return *team, true
+	}
+	return Team{}, false
+}
+
+// Resolve identifies a team from the ID and name Fotmob reports for it.
+// A name that matches an alias wins over a conflicting ID, and the ID is
+// remembered so later lookups can go straight through the ID index.
+func (r *Registry) Resolve(id int, name string) (Team, bool) {
+	key := Normalize(name)
+
+	r.mu.RLock()
+	byID, idOK := r.byID[id]
+	byName, nameOK := r.byAlias[key]
+	switch {
+	case idOK && (name == "" || !nameOK || byID == byName):
+		team := *byID
+		r.mu.RUnlock()
+		return team, true
+	case !nameOK:
+		r.mu.RUnlock()
+		return Team{}, false
+	case id == 0 || byName.ID == id:
+		team := *byName
+		r.mu.RUnlock()
+		return team, true
+	}
+	r.mu.RUnlock()
+
+	// Learning the ID changes the team in place, so copy it under the same lock
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	team := r.byAlias[key]
+	if team.ID != id {
+		r.learnID(team, id)
+	}
+	return *team, true
+}
+
+// All returns every registered team
+func (r *Registry) All() []Team {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	all := make([]Team, 0, len(r.teams))
+	for _, team := range r.teams {
+		all = append(all, *team)
+	}
+	return all
+}
+
+// AtVenue returns the teams whose home ground is the given venue
+func (r *Registry) AtVenue(venue string) []Team {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key := Normalize(venue)
+	found := []Team{}
+	for _, team := range r.teams {
+		if team.HomeVenue != "" && Normalize(team.HomeVenue) == key {
+			found = append(found, *team)
+		}
+	}
+	return found
+}
+
+// learnID records an upstream ID for a team matched by name. Callers must
+// hold the write lock.
+func (r *Registry) learnID(team *Team, id int) {
+	if existing, ok := r.byID[id]; ok && existing != team {
+		log.Printf("Team ID %d is registered to %s but upstream calls it %s, keeping the existing mapping",
+			id, existing.Name, team.Name)
+		return
+	}
+	if team.ID != 0 {
+		log.Printf("Team %s changed Fotmob ID from %d to %d", team.Name, team.ID, id)
+		delete(r.byID, team.ID)
+	}
+	r.setID(team, id)
+}
+
+// setID indexes a team under id. A logo derived from the previous ID
+// follows the new one. Callers must hold the write lock.
+func (r *Registry) setID(team *Team, id int) {
+	if team.LogoURL == "" || (team.ID != 0 && team.LogoURL == LogoURL(team.ID)) {
+		team.LogoURL = LogoURL(id)
+	}
+	team.ID = id
+	r.byID[id] = team
+}
+
+// LogoURL returns Fotmob's logo image for a team ID
+func LogoURL(id int) string {
+	return fmt.Sprintf("https://images.fotmob.com/image_resources/logo/teamlogo/%d.png", id)
+}
+
+// Normalize folds a team name for comparison: lowercase, punctuation and
+// repeated spaces removed, so "Maccabi Haifa", "maccabi-haifa" and
+// "Maccabi  Haifa" compare equal while "Maccabi Haifa U19" does not
+func Normalize(name string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteRune(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		case r == '"' || r == '\'' || r == '׳' || r == '״':
+			// Dropped so Hebrew abbreviations like בית"ר match with or without quotes
+		default:
+			space = true
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package teams
+
+// venueAliases lists other names upstream data uses for the seeded venues,
+// including sponsor and former names
+var venueAliases = map[string][]string{
+	SammyOferStadium:  {"Sammy Ofer", "Sami Ofer Stadium", "Sami Ofer", "Haifa International Stadium", "Sammy Ofer Stadium Haifa", "אצטדיון סמי עופר", "סמי עופר"},
+	BloomfieldStadium: {"Bloomfield", "אצטדיון בלומפילד"},
+	TurnerStadium:     {"Toto Turner Stadium", "Turner", "אצטדיון טרנר"},
+	TeddyStadium:      {"Teddy Kollek Stadium", "Teddy", "אצטדיון טדי"},
+	NetanyaStadium:    {"Netanya Municipal Stadium", "אצטדיון נתניה"},
+	DohaStadium:       {"Doha", "אצטדיון דוחא"},
+	GreenStadium:      {"Green Stadium Nof HaGalil", "האצטדיון הירוק"},
+}
+
+var venuesByAlias = indexVenues()
+
+func indexVenues() map[string]string {
+	index := make(map[string]string)
+	for venue, aliases := range venueAliases {
+		index[Normalize(venue)] = venue
+		for _, alias := range aliases {
+			index[Normalize(alias)] = venue
+		}
+	}
+	return index
+}
+
+// CanonicalVenue returns the seeded name of a venue given any of its known
+// names, or name itself for an unknown venue
+func CanonicalVenue(name string) string {
+	if venue, ok := venuesByAlias[Normalize(name)]; ok {
+		return venue
+	}
+	return name
+}
+
+// SameVenue reports whether two venue names refer to the same stadium
+func SameVenue(a, b string) bool {
+	if Normalize(a) == "" || Normalize(b) == "" {
+		return false
+	}
+	return Normalize(CanonicalVenue(a)) == Normalize(CanonicalVenue(b))
+}
+
+// venueSlugs are the names the seeded venues go by in URLs
+var venueSlugs = map[string]string{
+	SammyOferStadium:    "sammyofer",
+	BloomfieldStadium:   "bloomfield",
+	TurnerStadium:       "turner",
+	TeddyStadium:        "teddy",
+	NetanyaStadium:      "netanya",
+	DohaStadium:         "doha",
+	YudAlefStadium:      "yudalef",
+	GreenStadium:        "green",
+	HaMoshavaStadium:    "hamoshava",
+	KiryatShmonaStadium: "kiryatshmona",
+}
+
+// VenueSlug returns the URL name of a seeded venue given any of its names
+func VenueSlug(name string) (string, bool) {
+	slug, ok := venueSlugs[CanonicalVenue(name)]
+	return slug, ok
+}
+
+// VenueBySlug returns the seeded venue with the given URL name
+func VenueBySlug(slug string) (string, bool) {
+	for venue, s := range venueSlugs {
+		if s == slug {
+			return venue, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,559 @@
+// Package transit suggests public transport to and from the stadium from a
+// GTFS static feed on disk
+package transit
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+)
+
+// Transport modes, from GTFS route types
+const (
+	ModeTram      = "tram"
+	ModeSubway    = "subway"
+	ModeRail      = "rail"
+	ModeBus       = "bus"
+	ModeFerry     = "ferry"
+	ModeCableCar  = "cable-car"
+	ModeGondola   = "gondola"
+	ModeFunicular = "funicular"
+	ModeOther     = "other"
+)
+
+// modeOf maps a GTFS route_type, basic or extended, to a mode
+func modeOf(routeType int) string {
+	switch {
+	case routeType == 0 || routeType/100 == 9:
+		return ModeTram
+	case routeType == 1 || routeType/100 == 4:
+		return ModeSubway
+	case routeType == 2 || routeType == 12 || routeType/100 == 1:
+		return ModeRail
+	case routeType == 3 || routeType == 11 || routeType/100 == 2 || routeType/100 == 7 || routeType/100 == 8:
+		return ModeBus
+	case routeType == 4 || routeType/100 == 10 || routeType/100 == 12:
+		return ModeFerry
+	case routeType == 5:
+		return ModeCableCar
+	case routeType == 6 || routeType/100 == 13:
+		return ModeGondola
+	case routeType == 7 || routeType/100 == 14:
+		return ModeFunicular
+	}
+	return ModeOther
+}
+
+// Route is a line in the feed
+type Route struct {
+	ID        string `json:"id"`
+	ShortName string `json:"shortName"`
+	LongName  string `json:"longName"`
+	Mode      string `json:"mode"`
+	Agency    string `json:"agency"`
+	// Color is the line's hex color without "#", when the feed has one
+	Color string `json:"color,omitempty"`
+}
+
+// Stop is a stop within walking distance of the stadium
+type Stop struct {
+	ID   string  `json:"id"`
+	Name string  `json:"name"`
+	Lat  float64 `json:"lat"`
+	Lng  float64 `json:"lng"`
+	// Distance is in a straight line, in meters
+	Distance    int `json:"distanceMeters"`
+	WalkMinutes int `json:"walkMinutes"`
+}
+
+// trip is one run of a route. Its first and last calls are kept for every
+// trip; other calls only at nearby stops.
+type trip struct {
+	route    string
+	service  string
+	headsign string
+
+	firstSeq, lastSeq   int
+	firstStop, lastStop int
+	departs, arrives    int
+}
+
+// visit is a trip calling at a nearby stop, in seconds from the service
+// day's noon minus 12h as GTFS counts them
+type visit struct {
+	trip      int
+	stop      int
+	arrival   int
+	departure int
+}
+
+// service is when a service_id runs, from calendar.txt and
+// calendar_dates.txt
+type service struct {
+	weekdays   [7]bool // indexed by time.Weekday
+	start, end int     // yyyymmdd, zero when the service is only listed in calendar_dates.txt
+	added      map[int]bool
+	removed    map[int]bool
+}
+
+func (s *service) runsOn(date int, weekday time.Weekday) bool {
+	if s.removed[date] {
+		return false
+	}
+	if s.added[date] {
+		return true
+	}
+	return s.start != 0 && date >= s.start && date <= s.end && s.weekdays[weekday]
+}
+
+// feedData is what is kept of a feed for one stadium position
+type feedData struct {
+	location  *time.Location
+	stops     []Stop
+	stopNames []string // every stop in the feed, for trip ends
+	routes    map[string]Route
+	trips     []trip
+	services  map[string]*service
+	visits    []visit
+}
+
+// Walking is estimated from the straight-line distance, stretched for
+// streets that don't run straight to the stadium
+const (
+	walkSpeed  = 80.0 // meters a minute
+	walkDetour = 1.3
+)
+
+// walkMinutes estimates the walk over a straight-line distance in meters
+func walkMinutes(distance float64) int {
+	return int(math.Ceil(distance * walkDetour / walkSpeed))
+}
+
+// distance returns the great-circle distance between two positions in
+// meters
+func distance(a, b Point) float64 {
+	const earthRadius = 6371000.0
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+// opener opens a file of the feed
+type opener func(name string) (io.ReadCloser, error)
+
+// openFeed opens a feed given as a .zip file or an unpacked directory
+func openFeed(path string) (opener, func() error, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.IsDir() {
+		return func(name string) (io.ReadCloser, error) {
+			return os.Open(filepath.Join(path, name))
+		}, func() error { return nil }, nil
+	}
+
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return func(name string) (io.ReadCloser, error) {
+		// Some feeds are zipped with a top-level directory
+		for _, file := range archive.File {
+			if file.Name == name || strings.HasSuffix(file.Name, "/"+name) {
+				return file.Open()
+			}
+		}
+		return nil, os.ErrNotExist
+	}, archive.Close, nil
+}
+
+// table reads a feed file row by row, with columns looked up by name once
+type table struct {
+	name    string
+	file    io.ReadCloser
+	reader  *csv.Reader
+	columns map[string]int
+	row     []string
+	err     error
+}
+
+func openTable(open opener, name string) (*table, error) {
+	file, err := open(name)
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	columns := make(map[string]int)
+	for i, column := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))] = i
+	}
+	return &table{name: name, file: file, reader: reader, columns: columns}, nil
+}
+
+// index returns a column's position, or -1 if the file lacks it
+func (t *table) index(column string) int {
+	if i, ok := t.columns[column]; ok {
+		return i
+	}
+	return -1
+}
+
+// require returns the positions of columns the file must have
+func (t *table) require(columns ...string) ([]int, error) {
+	indexes := make([]int, len(columns))
+	for i, column := range columns {
+		if indexes[i] = t.index(column); indexes[i] < 0 {
+			return nil, fmt.Errorf("%s: missing %s column", t.name, column)
+		}
+	}
+	return indexes, nil
+}
+
+func (t *table) next() bool {
+	t.row, t.err = t.reader.Read()
+	if t.err == io.EOF {
+		t.err = nil
+		return false
+	}
+	if t.err != nil {
+		t.err = fmt.Errorf("%s: %v", t.name, t.err)
+		return false
+	}
+	return true
+}
+
+// field returns the value at a column position, "" for a missing column
+func (t *table) field(i int) string {
+	if i < 0 || i >= len(t.row) {
+		return ""
+	}
+	return strings.TrimSpace(t.row[i])
+}
+
+func (t *table) Close() error {
+	return t.file.Close()
+}
+
+// load reads the feed at path, keeping the calls at stops within radius
+// meters of center
+func load(path string, center Point, radius float64) (*feedData, error) {
+	open, closeFeed, err := openFeed(path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFeed()
+
+	data := &feedData{
+		location: i18n.Jerusalem,
+		routes:   make(map[string]Route),
+		services: make(map[string]*service),
+	}
+
+	agencies, err := data.loadAgencies(open)
+	if err != nil {
+		return nil, err
+	}
+	stopIndex, nearby, err := data.loadStops(open, center, radius)
+	if err != nil {
+		return nil, err
+	}
+	if err := data.loadRoutes(open, agencies); err != nil {
+		return nil, err
+	}
+	if err := data.loadServices(open); err != nil {
+		return nil, err
+	}
+	tripIndex, err := data.loadTrips(open)
+	if err != nil {
+		return nil, err
+	}
+	if err := data.loadStopTimes(open, stopIndex, tripIndex, nearby); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// loadAgencies reads agency names and the feed's timezone
+func (d *feedData) loadAgencies(open opener) (map[string]string, error) {
+	t, err := openTable(open, "agency.txt")
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+
+	id, name, timezone := t.index("agency_id"), t.index("agency_name"), t.index("agency_timezone")
+	agencies := make(map[string]string)
+	for t.next() {
+		agencies[t.field(id)] = t.field(name)
+		if tz := t.field(timezone); tz != "" {
+			if location, err := time.LoadLocation(tz); err == nil {
+				d.location = location
+			}
+		}
+	}
+	return agencies, t.err
+}
+
+// loadStops keeps every stop's name and the details of those within
+// radius, returning the index of each stop ID and, by that index, where
+// nearby stops are in d.stops
+func (d *feedData) loadStops(open opener, center Point, radius float64) (map[string]int, map[int]int, error) {
+	t, err := openTable(open, "stops.txt")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer t.Close()
+
+	columns, err := t.require("stop_id", "stop_name", "stop_lat", "stop_lon")
+	if err != nil {
+		return nil, nil, err
+	}
+	stopIndex := make(map[string]int)
+	nearby := make(map[int]int)
+	for t.next() {
+		id, name := t.field(columns[0]), t.field(columns[1])
+		stopIndex[id] = len(d.stopNames)
+		d.stopNames = append(d.stopNames, name)
+
+		lat, latErr := strconv.ParseFloat(t.field(columns[2]), 64)
+		lng, lngErr := strconv.ParseFloat(t.field(columns[3]), 64)
+		if latErr != nil || lngErr != nil {
+			continue
+		}
+		position := Point{Lat: lat, Lng: lng}
+		if meters := distance(center, position); meters <= radius {
+			nearby[stopIndex[id]] = len(d.stops)
+			d.stops = append(d.stops, Stop{
+				ID:          id,
+				Name:        name,
+				Lat:         lat,
+				Lng:         lng,
+				Distance:    int(math.Round(meters)),
+				WalkMinutes: walkMinutes(meters),
+			})
+		}
+	}
+	return stopIndex, nearby, t.err
+}
+
+func (d *feedData) loadRoutes(open opener, agencies map[string]string) error {
+	t, err := openTable(open, "routes.txt")
+	if err != nil {
+		return err
+	}
+	defer t.Close()
+
+	columns, err := t.require("route_id", "route_type")
+	if err != nil {
+		return err
+	}
+	agency, short, long, color := t.index("agency_id"), t.index("route_short_name"), t.index("route_long_name"), t.index("route_color")
+	for t.next() {
+		routeType, _ := strconv.Atoi(t.field(columns[1]))
+		route := Route{
+			ID:        t.field(columns[0]),
+			ShortName: t.field(short),
+			LongName:  t.field(long),
+			Mode:      modeOf(routeType),
+			Agency:    agencies[t.field(agency)],
+			Color:     t.field(color),
+		}
+		// A feed with a single agency may leave agency_id out
+		if route.Agency == "" && len(agencies) == 1 {
+			for _, name := range agencies {
+				route.Agency = name
+			}
+		}
+		d.routes[route.ID] = route
+	}
+	return t.err
+}
+
+// loadServices reads calendar.txt and calendar_dates.txt; a feed needs at
+// least one of them
+func (d *feedData) loadServices(open opener) error {
+	found := false
+
+	if t, err := openTable(open, "calendar.txt"); err == nil {
+		defer t.Close()
+		found = true
+		columns, err := t.require("service_id", "sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "start_date", "end_date")
+		if err != nil {
+			return err
+		}
+		for t.next() {
+			s := d.service(t.field(columns[0]))
+			for day := time.Sunday; day <= time.Saturday; day++ {
+				s.weekdays[day] = t.field(columns[1+int(day)]) == "1"
+			}
+			s.start, _ = strconv.Atoi(t.field(columns[8]))
+			s.end, _ = strconv.Atoi(t.field(columns[9]))
+		}
+		if t.err != nil {
+			return t.err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if t, err := openTable(open, "calendar_dates.txt"); err == nil {
+		defer t.Close()
+		found = true
+		columns, err := t.require("service_id", "date", "exception_type")
+		if err != nil {
+			return err
+		}
+		for t.next() {
+			s := d.service(t.field(columns[0]))
+			date, _ := strconv.Atoi(t.field(columns[1]))
+			switch t.field(columns[2]) {
+			case "1":
+				s.added[date] = true
+			case "2":
+				s.removed[date] = true
+			}
+		}
+		if t.err != nil {
+			return t.err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if !found {
+		return errors.New("the feed has neither calendar.txt nor calendar_dates.txt")
+	}
+	return nil
+}
+
+func (d *feedData) service(id string) *service {
+	s, ok := d.services[id]
+	if !ok {
+		s = &service{added: make(map[int]bool), removed: make(map[int]bool)}
+		d.services[id] = s
+	}
+	return s
+}
+
+func (d *feedData) loadTrips(open opener) (map[string]int, error) {
+	t, err := openTable(open, "trips.txt")
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+
+	columns, err := t.require("route_id", "service_id", "trip_id")
+	if err != nil {
+		return nil, err
+	}
+	headsign := t.index("trip_headsign")
+	tripIndex := make(map[string]int)
+	for t.next() {
+		tripIndex[t.field(columns[2])] = len(d.trips)
+		d.trips = append(d.trips, trip{
+			route:    t.field(columns[0]),
+			service:  t.field(columns[1]),
+			headsign: t.field(headsign),
+			firstSeq: math.MaxInt,
+			lastSeq:  -1,
+		})
+	}
+	return tripIndex, t.err
+}
+
+// loadStopTimes streams stop_times.txt, which holds most of a feed, keeping
+// each trip's ends and its calls at nearby stops
+func (d *feedData) loadStopTimes(open opener, stopIndex, tripIndex map[string]int, nearby map[int]int) error {
+	t, err := openTable(open, "stop_times.txt")
+	if err != nil {
+		return err
+	}
+	defer t.Close()
+
+	columns, err := t.require("trip_id", "arrival_time", "departure_time", "stop_id", "stop_sequence")
+	if err != nil {
+		return err
+	}
+	for t.next() {
+		tripID, ok := tripIndex[t.field(columns[0])]
+		if !ok {
+			continue
+		}
+		stopID, ok := stopIndex[t.field(columns[3])]
+		if !ok {
+			continue
+		}
+		// Calls between timepoints may have no times
+		arrival, arrivalOK := parseGTFSTime(t.field(columns[1]))
+		departure, departureOK := parseGTFSTime(t.field(columns[2]))
+		if !arrivalOK && !departureOK {
+			continue
+		}
+		if !arrivalOK {
+			arrival = departure
+		}
+		if !departureOK {
+			departure = arrival
+		}
+		seq, _ := strconv.Atoi(t.field(columns[4]))
+
+		tr := &d.trips[tripID]
+		if seq < tr.firstSeq {
+			tr.firstSeq, tr.firstStop, tr.departs = seq, stopID, departure
+		}
+		if seq > tr.lastSeq {
+			tr.lastSeq, tr.lastStop, tr.arrives = seq, stopID, arrival
+		}
+		if stop, ok := nearby[stopID]; ok {
+			d.visits = append(d.visits, visit{trip: tripID, stop: stop, arrival: arrival, departure: departure})
+		}
+	}
+	return t.err
+}
+
+// parseGTFSTime parses an H:MM:SS time, which passes 24:00:00 for trips
+// running past midnight, into seconds
+func parseGTFSTime(value string) (int, bool) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return 0, false
+	}
+	var seconds int
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, false
+		}
+		seconds = seconds*60 + n
+	}
+	return seconds, true
+}
+
+// logLoad reports a finished load
+func logLoad(path string, data *feedData, radius float64, took time.Duration) {
+	log.Printf("Loaded GTFS feed %s: %d stops within %.0fm, %d calls there, in %s",
+		path, len(data.stops), radius, len(data.visits), took.Round(time.Millisecond))
+}
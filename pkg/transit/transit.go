@@ -0,0 +1,280 @@
+package transit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNoFeed is returned when no GTFS feed is configured
+var ErrNoFeed = errors.New("no GTFS feed is configured")
+
+// DefaultRadius is how far from the stadium, in meters, a stop counts as
+// within walking distance
+const DefaultRadius = 1000
+
+// DefaultLimit is how many trips are suggested each way
+const DefaultLimit = 6
+
+// Point is a WGS 84 position
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// Call is a trip calling at a stop
+type Call struct {
+	Stop string    `json:"stop"`
+	Time time.Time `json:"time"`
+}
+
+// Trip is a suggested run of a line, at the nearby stop closest to the
+// stadium it calls at
+type Trip struct {
+	Route    Route  `json:"route"`
+	Headsign string `json:"headsign"`
+	Stop     Stop   `json:"stop"`
+	// Time is when the trip calls at Stop
+	Time time.Time `json:"time"`
+	// StadiumTime is when passengers of an arrival reach the stadium on
+	// foot, or when to leave the stadium to catch a departure
+	StadiumTime time.Time `json:"stadiumTime"`
+	// From and To are the trip's first and last stops
+	From Call `json:"from"`
+	To   Call `json:"to"`
+}
+
+// Query asks for trips reaching the stadium between ArriveFrom and
+// ArriveBy, and leaving it between LeaveFrom and LeaveUntil, counting the
+// walk to and from the stops. A zero range is skipped.
+type Query struct {
+	Center     Point
+	ArriveFrom time.Time
+	ArriveBy   time.Time
+	LeaveFrom  time.Time
+	LeaveUntil time.Time
+	// Limit is the most trips suggested each way, DefaultLimit if zero
+	Limit int
+}
+
+// Guidance is how to get to and from the stadium by public transport
+type Guidance struct {
+	// Stops within walking distance, nearest first
+	Stops []Stop `json:"stops"`
+	// Arrivals are the latest trip of each line that gets to the stadium
+	// in time, earliest first
+	Arrivals []Trip `json:"arrivals"`
+	// Departures are the first trip of each line after leaving the
+	// stadium, earliest first
+	Departures []Trip `json:"departures"`
+	// FeedUpdated is when the GTFS feed file last changed
+	FeedUpdated time.Time `json:"feedUpdated"`
+}
+
+// Feed is a GTFS static feed, a .zip file or an unpacked directory. Only
+// the calls at stops near the stadium are kept in memory; the feed is read
+// again when the file changes or the stadium moves.
+type Feed struct {
+	Path string
+	// Radius is the walking distance in meters, DefaultRadius if zero
+	Radius float64
+
+	mu       sync.Mutex
+	data     *feedData
+	modTime  time.Time
+	center   Point
+	loadErr  error
+	failedAt time.Time
+}
+
+// NewFeed creates a feed reading path; it is loaded on first use
+func NewFeed(path string, radius float64) *Feed {
+	if radius <= 0 {
+		radius = DefaultRadius
+	}
+	return &Feed{Path: path, Radius: radius}
+}
+
+// Load reads the feed for a stadium at center unless it is already loaded
+// and unchanged
+func (f *Feed) Load(center Point) error {
+	if f == nil || f.Path == "" {
+		return ErrNoFeed
+	}
+	_, _, err := f.current(center)
+	return err
+}
+
+// current returns the feed data for center, loading it when needed. A
+// failed load isn't retried for a minute unless the file changes.
+func (f *Feed) current(center Point) (*feedData, time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("GTFS feed unavailable: %v", err)
+	}
+	if f.data != nil && info.ModTime().Equal(f.modTime) && center == f.center {
+		return f.data, f.modTime, nil
+	}
+	if f.loadErr != nil && info.ModTime().Equal(f.modTime) && center == f.center && time.Since(f.failedAt) < time.Minute {
+		return nil, time.Time{}, f.loadErr
+	}
+
+	started := time.Now()
+	data, err := load(f.Path, center, f.Radius)
+	f.modTime, f.center = info.ModTime(), center
+	if err != nil {
+		f.data, f.loadErr, f.failedAt = nil, fmt.Errorf("error loading GTFS feed %s: %v", f.Path, err), time.Now()
+		return nil, time.Time{}, f.loadErr
+	}
+	logLoad(f.Path, data, f.Radius, time.Since(started))
+	f.data, f.loadErr = data, nil
+	return data, f.modTime, nil
+}
+
+// Plan suggests trips to and from the stadium
+func (f *Feed) Plan(q Query) (Guidance, error) {
+	if f == nil || f.Path == "" {
+		return Guidance{}, ErrNoFeed
+	}
+	data, modTime, err := f.current(q.Center)
+	if err != nil {
+		return Guidance{}, err
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultLimit
+	}
+
+	// Visits refer to data.stops by position, so sort a copy
+	stops := append([]Stop{}, data.stops...)
+	sort.SliceStable(stops, func(i, j int) bool { return stops[i].Distance < stops[j].Distance })
+	guidance := Guidance{Stops: stops, Arrivals: []Trip{}, Departures: []Trip{}, FeedUpdated: modTime.UTC()}
+	if !q.ArriveBy.IsZero() {
+		guidance.Arrivals = data.suggest(q.ArriveFrom, q.ArriveBy, true, q.Limit)
+	}
+	if !q.LeaveFrom.IsZero() {
+		guidance.Departures = data.suggest(q.LeaveFrom, q.LeaveUntil, false, q.Limit)
+	}
+	return guidance, nil
+}
+
+// suggest picks, for each line and direction, the latest arrival reaching
+// the stadium within [from, to] or the first departure catchable within it
+func (d *feedData) suggest(from, to time.Time, arriving bool, limit int) []Trip {
+	type candidate struct {
+		trip, stop int
+		origin     time.Time // of the service day
+		at         time.Time // at the stop
+		stadium    time.Time
+	}
+
+	// The nearest stop a trip calls at within the range
+	byTrip := make(map[int]candidate)
+	for _, day := range d.serviceDays(from, to) {
+		for _, v := range d.visits {
+			t := d.trips[v.trip]
+			s, ok := d.services[t.service]
+			if !ok || !s.runsOn(day.date, day.weekday) {
+				continue
+			}
+			walk := time.Duration(d.stops[v.stop].WalkMinutes) * time.Minute
+			c := candidate{trip: v.trip, stop: v.stop, origin: day.origin}
+			if arriving {
+				c.at = day.origin.Add(time.Duration(v.arrival) * time.Second)
+				c.stadium = c.at.Add(walk)
+			} else {
+				c.at = day.origin.Add(time.Duration(v.departure) * time.Second)
+				c.stadium = c.at.Add(-walk)
+			}
+			if c.stadium.Before(from) || c.stadium.After(to) {
+				continue
+			}
+			if best, ok := byTrip[v.trip]; ok && d.stops[best.stop].Distance <= d.stops[v.stop].Distance {
+				continue
+			}
+			byTrip[v.trip] = c
+		}
+	}
+
+	// One trip per line and direction: the last one in for arrivals, the
+	// first one out for departures
+	type line struct{ route, headsign string }
+	byLine := make(map[line]candidate)
+	for _, c := range byTrip {
+		t := d.trips[c.trip]
+		key := line{t.route, t.headsign}
+		best, ok := byLine[key]
+		if !ok || (arriving && c.stadium.After(best.stadium)) || (!arriving && c.stadium.Before(best.stadium)) {
+			byLine[key] = c
+		}
+	}
+
+	candidates := make([]candidate, 0, len(byLine))
+	for _, c := range byLine {
+		candidates = append(candidates, c)
+	}
+	// Closest to the match first, so the limit keeps the most convenient
+	sort.Slice(candidates, func(i, j int) bool {
+		if !candidates[i].stadium.Equal(candidates[j].stadium) {
+			if arriving {
+				return candidates[i].stadium.After(candidates[j].stadium)
+			}
+			return candidates[i].stadium.Before(candidates[j].stadium)
+		}
+		return d.trips[candidates[i].trip].route < d.trips[candidates[j].trip].route
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].stadium.Before(candidates[j].stadium) })
+
+	trips := make([]Trip, 0, len(candidates))
+	for _, c := range candidates {
+		t := d.trips[c.trip]
+		route, ok := d.routes[t.route]
+		if !ok {
+			route = Route{ID: t.route, Mode: ModeOther}
+		}
+		trips = append(trips, Trip{
+			Route:       route,
+			Headsign:    t.headsign,
+			Stop:        d.stops[c.stop],
+			Time:        c.at.UTC(),
+			StadiumTime: c.stadium.UTC(),
+			From:        Call{Stop: d.stopNames[t.firstStop], Time: c.origin.Add(time.Duration(t.departs) * time.Second).UTC()},
+			To:          Call{Stop: d.stopNames[t.lastStop], Time: c.origin.Add(time.Duration(t.arrives) * time.Second).UTC()},
+		})
+	}
+	return trips
+}
+
+// serviceDay is a service date and the instant its GTFS times count from
+type serviceDay struct {
+	date    int // yyyymmdd
+	weekday time.Weekday
+	origin  time.Time
+}
+
+// serviceDays lists the service dates whose trips may call within [from,
+// to]: trips run past midnight, so the day before is included
+func (d *feedData) serviceDays(from, to time.Time) []serviceDay {
+	start := from.In(d.location)
+	end := to.In(d.location)
+	var days []serviceDay
+	for day := time.Date(start.Year(), start.Month(), start.Day()-1, 0, 0, 0, 0, d.location); !day.After(end); day = day.AddDate(0, 0, 1) {
+		// GTFS times count from noon minus 12h, which differs from
+		// midnight on daylight saving days
+		noon := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, d.location)
+		days = append(days, serviceDay{
+			date:    day.Year()*10000 + int(day.Month())*100 + day.Day(),
+			weekday: day.Weekday(),
+			origin:  noon.Add(-12 * time.Hour),
+		})
+	}
+	return days
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/api"
+	"github.com/MichaelBabushkin/sammy_po/pkg/cache"
+	"github.com/MichaelBabushkin/sammy_po/pkg/fotmob"
+	"github.com/MichaelBabushkin/sammy_po/pkg/outbound"
+	"github.com/MichaelBabushkin/sammy_po/pkg/scraper"
+)
+
+// adminRoutes registers the admin JSON endpoints behind RequireAdmin
+func (s *Server) adminRoutes(mux *http.ServeMux) {
+	admin := RequireAdmin(s.config.Admin)
+	handle := func(pattern string, h http.HandlerFunc) {
+		mux.Handle(pattern, admin(h))
+	}
+
+	handle("GET /api/admin/token", s.handleAdminToken)
+	handle("PUT /api/admin/token/source", s.handleAdminForceSource)
+	handle("GET /api/admin/refreshes", s.handleAdminRefreshes)
+	handle("GET /api/admin/caches", s.handleAdminCaches)
+	handle("DELETE /api/admin/caches", s.handleAdminPurgeCaches)
+	handle("DELETE /api/admin/caches/{name}", s.handleAdminPurgeCaches)
+	handle("GET /api/admin/upstream", s.handleAdminUpstream)
+	handle("GET /api/admin/budget", s.handleAdminBudget)
+	handle("GET /api/admin/notifications", s.handleAdminNotifications)
+}
+
+func (s *Server) handleAdminToken(w http.ResponseWriter, r *http.Request) {
+	sourceNames := []string{}
+	for _, source := range scraper.Sources() {
+		sourceNames = append(sourceNames, source.Name())
+	}
+
+	response := map[string]interface{}{
+		"source":       scraper.CurrentSource(),
+		"forcedSource": scraper.ForcedSource(),
+		"sources":      sourceNames,
+		"degraded":     s.degraded.status(),
+	}
+
+	status, err := api.StoredTokenStatus()
+	if err != nil {
+		response["error"] = "no stored token"
+		writeJSON(w, http.StatusOK, response)
+		return
+	}
+
+	response["tokenPreview"] = scraper.TruncateToken(status.Token)
+	response["updatedAt"] = status.UpdatedAt.Format(time.RFC3339)
+	response["age"] = time.Since(status.UpdatedAt).Round(time.Second).String()
+
+	if info, err := api.DecodeToken(status.Token); err != nil {
+		response["error"] = err.Error()
+	} else {
+		response["decoded"] = info
+		response["expired"] = info.Expired()
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) handleAdminForceSource(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Source string `json:"source"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusBadRequest)
+		return
+	}
+
+	if err := scraper.ForceSource(body.Source); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"forcedSource": scraper.ForcedSource(),
+	})
+}
+
+func (s *Server) handleAdminRefreshes(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"history": scraper.RefreshHistory(),
+		"sources": scraper.RefreshStats(),
+	})
+}
+
+func (s *Server) handleAdminCaches(w http.ResponseWriter, r *http.Request) {
+	caches := make(map[string][]cache.EntryInfo)
+	for name, c := range fotmob.Caches() {
+		caches[name] = c.Entries()
+	}
+	writeJSON(w, http.StatusOK, caches)
+}
+
+func (s *Server) handleAdminPurgeCaches(w http.ResponseWriter, r *http.Request) {
+	caches := fotmob.Caches()
+
+	if name := r.PathValue("name"); name != "" {
+		c, ok := caches[name]
+		if !ok {
+			http.Error(w, "Unknown cache", http.StatusNotFound)
+			return
+		}
+		caches = map[string]*cache.Cache{name: c}
+	}
+
+	purged := make(map[string]int)
+	for name, c := range caches {
+		purged[name] = c.Purge()
+	}
+	log.Printf("[%s] Purged caches: %v", RequestIDFrom(r.Context()), purged)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"purged": purged,
+	})
+}
+
+func (s *Server) handleAdminUpstream(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, fotmob.Upstream())
+}
+
+func (s *Server) handleAdminBudget(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, outbound.CurrentUsage())
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxValidators bounds the Last-Modified memo; it is simply reset when full
+const maxValidators = 1000
+
+// validatorMemo remembers when each representation first appeared, so an
+// unchanged response keeps its Last-Modified even though it is refetched
+type validatorMemo struct {
+	mu      sync.Mutex
+	entries map[string]validator
+}
+
+type validator struct {
+	etag     string
+	modified time.Time
+}
+
+func newValidatorMemo() *validatorMemo {
+	return &validatorMemo{entries: make(map[string]validator)}
+}
+
+// lastModified returns the time the representation with etag was first
+// seen under key, recording fetchedAt if it is new
+func (m *validatorMemo) lastModified(key, etag string, fetchedAt time.Time) time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if v, ok := m.entries[key]; ok && v.etag == etag && v.modified.Before(fetchedAt) {
+		return v.modified
+	}
+
+	if len(m.entries) >= maxValidators {
+		m.entries = make(map[string]validator)
+	}
+	m.entries[key] = validator{etag: etag, modified: fetchedAt}
+	return fetchedAt
+}
+
+// writeCacheable sends v as JSON with validators; see writeValidated
+func (s *Server) writeCacheable(w http.ResponseWriter, r *http.Request, v interface{}, fetchedAt time.Time, maxAge time.Duration) {
+	var body bytes.Buffer
+	if err := json.NewEncoder(&body).Encode(v); err != nil {
+		log.Printf("Error encoding response: %v", err)
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+		return
+	}
+	s.writeValidated(w, r, body.Bytes(), "application/json", fetchedAt, maxAge)
+}
+
+// writeValidated sends body with a strong ETag, Last-Modified and a
+// max-age, answering 304 Not Modified when the request's validators match.
+// The Gzip middleware tags the compressed variant separately. fetchedAt
+// is when the data was fetched upstream; a zero maxAge means clients must
+// revalidate every time.
+func (s *Server) writeValidated(w http.ResponseWriter, r *http.Request, body []byte, contentType string, fetchedAt time.Time, maxAge time.Duration) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	// Pages, windows and localized variants share a path, so key on the
+	// query, in a canonical order, and the language too
+	key := r.URL.Path + "?" + r.URL.Query().Encode() + "|" + w.Header().Get("Content-Language")
+	modified := s.validators.lastModified(key, etag, fetchedAt.UTC().Truncate(time.Second))
+
+	h := w.Header()
+	h.Set("ETag", etag)
+	h.Set("Last-Modified", modified.Format(http.TimeFormat))
+	if maxAge > 0 {
+		h.Set("Cache-Control", fmt.Sprintf("max-age=%d", int(maxAge.Seconds())))
+	} else {
+		h.Set("Cache-Control", "no-cache")
+	}
+
+	if notModified(r, etag, modified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	h.Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// notModified evaluates If-None-Match, falling back to If-Modified-Since
+// only when no entity tags were sent (RFC 9110 section 13.2.2)
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		if since, err := http.ParseTime(ims); err == nil {
+			return !modified.After(since)
+		}
+	}
+	return false
+}
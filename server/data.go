@@ -0,0 +1,331 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/fotmob"
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+	"github.com/MichaelBabushkin/sammy_po/pkg/schedule"
+	"github.com/MichaelBabushkin/sammy_po/pkg/stadium"
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+	"github.com/MichaelBabushkin/sammy_po/pkg/transit"
+)
+
+// dataSource describes where data served to a client came from
+type dataSource struct {
+	// Provider names the fixture provider that answered, such as "fotmob"
+	// or "feed". Meta reports an empty one as "fotmob".
+	Provider  string
+	FetchedAt time.Time
+	Stale     bool
+	MaxAge    time.Duration
+}
+
+// The data layer shared by the JSON API and the HTML pages. Each method
+// refreshes the token if needed, asks the fixture providers in priority
+// order, and falls back to stored data when they all fail. Admin overrides
+// are merged over whatever was found. Fotmob errors feed the degraded-mode
+// state machine. Upstream calls are cancelled when ctx, normally the
+// incoming request's, ends.
+
+func (s *Server) upcomingMatches(ctx context.Context, locale i18n.Locale) ([]interface{}, dataSource, error) {
+	fixtures, source, err := s.leagueFixtures(ctx)
+	if err != nil {
+		return nil, dataSource{}, err
+	}
+	matches := fotmob.UpcomingSammyOferMatches(fixtures, locale)
+	if source.Stale {
+		return matches, source, nil
+	}
+
+	// Cache until shortly before the next kickoff, at most an hour
+	source.MaxAge = time.Hour
+	if kickoff, ok := fotmob.NextKickoff(matches); ok {
+		source.MaxAge = fotmob.KickoffMaxAge(kickoff)
+	}
+	return matches, source, nil
+}
+
+func (s *Server) venueConflicts(ctx context.Context, venue string, window time.Duration, locale i18n.Locale) (schedule.Report, dataSource, error) {
+	fixtures, source, err := s.leagueFixtures(ctx)
+	if err != nil {
+		return schedule.Report{}, dataSource{}, err
+	}
+	report := schedule.Analyze(fixtures, venue, window, locale)
+	if !source.Stale {
+		source.MaxAge = time.Hour
+	}
+	return report, source, nil
+}
+
+func (s *Server) busyWindows(ctx context.Context, locale i18n.Locale) ([]schedule.BusyWindow, dataSource, error) {
+	fixtures, source, err := s.leagueFixtures(ctx)
+	if err != nil {
+		return nil, dataSource{}, err
+	}
+	windows := s.occupancyModel().Windows(schedule.ForVenue(fixtures, teams.SammyOferStadium, locale))
+	if !source.Stale {
+		source.MaxAge = time.Hour
+	}
+	return windows, source, nil
+}
+
+// Matchday timings for transit suggestions
+const (
+	// arriveMargin leaves time to get through the gates before kickoff
+	arriveMargin = 20 * time.Minute
+	// finalWhistle is how long after kickoff a match usually ends
+	finalWhistle = 115 * time.Minute
+	// leaveWindow is how long after the final whistle departures are
+	// suggested for
+	leaveWindow = 2 * time.Hour
+)
+
+var (
+	errNotAtStadium       = errors.New("no upcoming match at Sammy Ofer with this ID")
+	errTransitUnavailable = errors.New("transit suggestions are unavailable")
+)
+
+// matchTransit suggests public transport to and from an upcoming match at
+// Sammy Ofer: arriving between gates opening and shortly before kickoff,
+// and leaving after the final whistle
+func (s *Server) matchTransit(ctx context.Context, matchID int, locale i18n.Locale) (MatchTransit, dataSource, error) {
+	if s.transit == nil {
+		return MatchTransit{}, dataSource{}, fmt.Errorf("%w: %v", errTransitUnavailable, transit.ErrNoFeed)
+	}
+	fixtures, source, err := s.leagueFixtures(ctx)
+	if err != nil {
+		return MatchTransit{}, dataSource{}, err
+	}
+
+	var window *schedule.BusyWindow
+	for _, w := range s.occupancyModel().Windows(schedule.ForVenue(fixtures, teams.SammyOferStadium, locale)) {
+		if w.Match.ID == matchID {
+			window = &w
+			break
+		}
+	}
+	if window == nil {
+		return MatchTransit{}, dataSource{}, errNotAtStadium
+	}
+
+	center, ok := s.stadiumCenter()
+	if !ok {
+		return MatchTransit{}, dataSource{}, fmt.Errorf("%w: the stadium profile has no coordinates", errTransitUnavailable)
+	}
+	result := MatchTransit{Match: window.Match, Venue: s.sammyOfer().Info(locale).Name}
+	query := transit.Query{Center: center}
+	if !window.Tentative {
+		arriveBy := window.Kickoff.Add(-arriveMargin)
+		whistle := window.Kickoff.Add(finalWhistle)
+		result.GatesOpen, result.ArriveBy, result.FinalWhistle = window.GatesOpen, &arriveBy, &whistle
+		query.ArriveFrom, query.ArriveBy = *window.GatesOpen, arriveBy
+		query.LeaveFrom, query.LeaveUntil = whistle, whistle.Add(leaveWindow)
+	}
+	if result.Guidance, err = s.transit.Plan(query); err != nil {
+		return MatchTransit{}, dataSource{}, fmt.Errorf("%w: %v", errTransitUnavailable, err)
+	}
+
+	// A new feed or a moved stadium changes the suggestions too
+	for _, t := range []time.Time{result.FeedUpdated, s.sammyOfer().UpdatedAt} {
+		if t.After(source.FetchedAt) {
+			source.FetchedAt = t
+		}
+	}
+	if !source.Stale {
+		source.MaxAge = time.Hour
+	}
+	return result, source, nil
+}
+
+// stadiumCenter is where the Sammy Ofer profile places the stadium
+func (s *Server) stadiumCenter() (transit.Point, bool) {
+	coordinates := s.sammyOfer().Coordinates
+	if coordinates == nil {
+		return transit.Point{}, false
+	}
+	return transit.Point{Lat: coordinates.Lat, Lng: coordinates.Lng}, true
+}
+
+// occupancyModel is the configured occupancy model, rating crowds against
+// the stadium profile's capacity unless the model sets its own
+func (s *Server) occupancyModel() schedule.Occupancy {
+	model := s.occupancy
+	if model.Capacity == 0 {
+		model.Capacity = s.sammyOfer().Capacity
+	}
+	return model
+}
+
+// stadiumInfo returns the Sammy Ofer profile in locale, dated by its last
+// edit
+func (s *Server) stadiumInfo(locale i18n.Locale) (stadium.Info, dataSource) {
+	profile := s.sammyOfer()
+	fetchedAt := profile.UpdatedAt
+	if fetchedAt.IsZero() {
+		fetchedAt = startedAt
+	}
+	return profile.Info(locale), dataSource{Provider: "manual", FetchedAt: fetchedAt, MaxAge: time.Hour}
+}
+
+// sammyOfer returns the Sammy Ofer profile, or the built-in one if the
+// stadium file lacks it
+func (s *Server) sammyOfer() stadium.Profile {
+	if profile, ok := s.stadiums.Get(stadium.SammyOfer); ok {
+		return profile
+	}
+	return stadium.Defaults[0]
+}
+
+// leagueFixtures returns every league fixture with the overrides applied.
+// Fresh ones start background venue lookups and are checked for schedule
+// changes.
+func (s *Server) leagueFixtures(ctx context.Context) ([]interface{}, dataSource, error) {
+	if s.fixtures.Has("fotmob") {
+		s.ensureFreshToken(ctx)
+	}
+
+	fetchedAt := time.Now()
+	fixtures, providerName, err := s.fixtures.FetchFixturesFrom(ctx, "")
+	if err != nil {
+		log.Printf("Error fetching matches: %v", err)
+		if ctx.Err() != nil {
+			return nil, dataSource{}, ctx.Err()
+		}
+
+		stored, storedAt, staleErr := fotmob.StoredLeagueMatches()
+		if staleErr != nil {
+			log.Printf("No stored matches to fall back on: %v", staleErr)
+			return nil, dataSource{}, err
+		}
+		return s.overrides.Apply(stored, false), s.withOverrides(dataSource{FetchedAt: storedAt, Stale: true}), nil
+	}
+	// Overrides go in before filtering so moved games are found or dropped
+	fixtures = s.overrides.Apply(fixtures, true)
+	if providerName == "fotmob" {
+		s.venues.trigger(fixtures)
+	}
+	s.changes.observe(providerName, fixtures)
+	return fixtures, s.withOverrides(dataSource{Provider: providerName, FetchedAt: fetchedAt}), nil
+}
+
+func (s *Server) matchDetails(ctx context.Context, matchID int) (*fotmob.MatchDetails, dataSource, error) {
+	// Manual fixtures aren't known to any provider
+	if details, ok := s.overrides.Details(matchID); ok {
+		return details, s.withOverrides(dataSource{Provider: details.Source, MaxAge: fotmob.MatchDetailsMaxAge(details)}), nil
+	}
+
+	// Only refresh the token when we actually have to go to Fotmob
+	owner, known := s.fixtures.Owner(matchID)
+	if !fotmob.MatchDetailsCached(matchID) && s.fixtures.Has("fotmob") && (!known || owner == "fotmob") {
+		s.ensureFreshToken(ctx)
+	}
+
+	details, err := fotmob.GetMatchDetails(ctx, s.fixtures, matchID)
+	if errors.Is(err, fotmob.ErrMatchNotFound) {
+		return nil, dataSource{}, err
+	}
+	if err != nil {
+		log.Printf("Error fetching match details for %d: %v", matchID, err)
+		if ctx.Err() != nil {
+			return nil, dataSource{}, ctx.Err()
+		}
+
+		stale, fetchedAt, ok := fotmob.StaleMatchDetails(matchID)
+		if !ok {
+			return nil, dataSource{}, err
+		}
+		return s.overrides.ApplyDetails(stale), s.withOverrides(dataSource{FetchedAt: fetchedAt, Stale: true}), nil
+	}
+
+	details = s.overrides.ApplyDetails(details)
+	return details, s.withOverrides(dataSource{
+		Provider:  details.Source,
+		FetchedAt: fotmob.MatchDetailsFetchedAt(matchID),
+		MaxAge:    fotmob.MatchDetailsMaxAge(details),
+	}), nil
+}
+
+// headToHead summarises past meetings of an upcoming fixture's clubs. The
+// fixture is found the way every match endpoint finds it, through the
+// providers with the overrides applied.
+func (s *Server) headToHead(ctx context.Context, matchID int) (*fotmob.HeadToHead, dataSource, error) {
+	fixtures, _, err := s.leagueFixtures(ctx)
+	if err != nil {
+		return nil, dataSource{}, err
+	}
+	fixture, ok := fixtureByID(fixtures, matchID)
+	if !ok {
+		return nil, dataSource{}, fotmob.ErrMatchNotFound
+	}
+
+	if !fotmob.HeadToHeadCached(matchID) {
+		s.ensureFreshToken(ctx)
+	}
+
+	h2h, err := fotmob.GetHeadToHead(ctx, s.client, fixture)
+	if err != nil {
+		log.Printf("Error building head-to-head for %d: %v", matchID, err)
+		if ctx.Err() != nil {
+			return nil, dataSource{}, ctx.Err()
+		}
+		s.degraded.observe(err)
+
+		stale, fetchedAt, ok := fotmob.StaleHeadToHead(matchID)
+		if !ok {
+			return nil, dataSource{}, err
+		}
+		return stale, dataSource{FetchedAt: fetchedAt, Stale: true}, nil
+	}
+
+	return h2h, dataSource{
+		FetchedAt: fotmob.HeadToHeadFetchedAt(matchID),
+		MaxAge:    fotmob.HeadToHeadMaxAge(h2h),
+	}, nil
+}
+
+// fixtureByID finds a raw league match by its ID, which providers give as
+// a string or a number
+func fixtureByID(fixtures []interface{}, matchID int) (map[string]interface{}, bool) {
+	for _, fixture := range fixtures {
+		matchMap, ok := fixture.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var id int
+		switch v := matchMap["id"].(type) {
+		case string:
+			id, _ = strconv.Atoi(v)
+		case float64:
+			id = int(v)
+		}
+		if id == matchID {
+			return matchMap, true
+		}
+	}
+	return nil, false
+}
+
+// withOverrides dates source no earlier than the last override change, so
+// an edit shows up as a modification to conditional requests
+func (s *Server) withOverrides(source dataSource) dataSource {
+	if updatedAt := s.overrides.UpdatedAt(); updatedAt.After(source.FetchedAt) {
+		source.FetchedAt = updatedAt
+	}
+	return source
+}
+
+// writeData sends data from the data layer as a cacheable JSON response
+func (s *Server) writeData(w http.ResponseWriter, r *http.Request, v interface{}, source dataSource) {
+	if source.Stale {
+		setStaleHeaders(w, source.FetchedAt)
+		source.MaxAge = 0
+	}
+	s.writeCacheable(w, r, v, source.FetchedAt, source.MaxAge)
+}
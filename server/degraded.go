@@ -0,0 +1,250 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/fotmob"
+	"github.com/MichaelBabushkin/sammy_po/pkg/outbound"
+)
+
+// Mode is the server's data mode: normal while Fotmob accepts our token,
+// degraded while it doesn't and only stored data can be served
+type Mode string
+
+const (
+	ModeNormal   Mode = "normal"
+	ModeDegraded Mode = "degraded"
+)
+
+const (
+	// estimatedRefreshTime is what we tell clients to wait while a
+	// background refresh is running
+	estimatedRefreshTime = time.Minute
+	minRefreshBackoff    = time.Minute
+	maxRefreshBackoff    = 30 * time.Minute
+)
+
+// DegradedStatus is a snapshot of the degraded-mode state machine
+type DegradedStatus struct {
+	Mode        Mode       `json:"mode"`
+	Since       *time.Time `json:"since,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+	Refreshing  bool       `json:"refreshing"`
+	Failures    int        `json:"failedRefreshes"`
+	NextAttempt *time.Time `json:"nextRefreshAttempt,omitempty"`
+}
+
+// degradedState moves between normal and degraded mode. Token errors from
+// upstream enter degraded mode and start a background refresh, retried with
+// exponential backoff; a successful refresh or upstream call leaves it.
+type degradedState struct {
+	mu          sync.Mutex
+	mode        Mode
+	since       time.Time
+	reason      string
+	refreshing  bool
+	failures    int
+	nextAttempt time.Time
+
+	refresh func() error
+}
+
+func newDegradedState(refresh func() error) *degradedState {
+	return &degradedState{mode: ModeNormal, refresh: refresh}
+}
+
+// active reports whether we are in degraded mode
+func (d *degradedState) active() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.mode == ModeDegraded
+}
+
+// observe inspects an upstream error and enters degraded mode if it means
+// we have no usable token
+func (d *degradedState) observe(err error) {
+	if !fotmob.IsTokenError(err) {
+		return
+	}
+
+	d.mu.Lock()
+	if d.mode != ModeDegraded {
+		log.Printf("Entering degraded mode: %v", err)
+		d.mode = ModeDegraded
+		d.since = time.Now()
+	}
+	d.reason = err.Error()
+	d.mu.Unlock()
+
+	d.triggerRefresh()
+}
+
+// recovered leaves degraded mode
+func (d *degradedState) recovered(why string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.mode == ModeDegraded {
+		log.Printf("Leaving degraded mode after %v: %s", time.Since(d.since).Round(time.Second), why)
+	}
+	d.mode = ModeNormal
+	d.since = time.Time{}
+	d.reason = ""
+	d.failures = 0
+	d.nextAttempt = time.Time{}
+}
+
+// triggerRefresh starts a background token refresh unless one is running
+// or the backoff after a failed one hasn't passed yet
+func (d *degradedState) triggerRefresh() {
+	d.mu.Lock()
+	if d.refreshing || time.Now().Before(d.nextAttempt) {
+		d.mu.Unlock()
+		return
+	}
+	d.refreshing = true
+	d.mu.Unlock()
+
+	go func() {
+		err := d.refresh()
+
+		d.mu.Lock()
+		d.refreshing = false
+		if err != nil {
+			d.failures++
+			backoff := minRefreshBackoff << (d.failures - 1)
+			if backoff > maxRefreshBackoff || backoff <= 0 {
+				backoff = maxRefreshBackoff
+			}
+			d.nextAttempt = time.Now().Add(backoff)
+			log.Printf("Background token refresh failed (%d in a row), next attempt in %v: %v", d.failures, backoff, err)
+			d.mu.Unlock()
+			return
+		}
+		d.mu.Unlock()
+
+		d.recovered("background token refresh succeeded")
+	}()
+}
+
+// retryAfter estimates when a client retry might succeed
+func (d *degradedState) retryAfter() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if wait := time.Until(d.nextAttempt); !d.refreshing && wait > 0 {
+		return wait + estimatedRefreshTime
+	}
+	return estimatedRefreshTime
+}
+
+// status returns a snapshot for the health and admin endpoints
+func (d *degradedState) status() DegradedStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	status := DegradedStatus{
+		Mode:       d.mode,
+		Reason:     d.reason,
+		Refreshing: d.refreshing,
+		Failures:   d.failures,
+	}
+	if !d.since.IsZero() {
+		since := d.since
+		status.Since = &since
+	}
+	if !d.nextAttempt.IsZero() {
+		next := d.nextAttempt
+		status.NextAttempt = &next
+	}
+	return status
+}
+
+// setStaleHeaders marks a response as served from stored data
+func setStaleHeaders(w http.ResponseWriter, fetchedAt time.Time) {
+	w.Header().Set("Warning", `110 - "Response is Stale"`)
+	w.Header().Set("X-Data-Stale", "true")
+	w.Header().Set("X-Data-Fetched-At", fetchedAt.UTC().Format(time.RFC3339))
+}
+
+// statusClientClosedRequest is logged when the client went away before we
+// could answer (nginx's convention; nobody receives it)
+const statusClientClosedRequest = 499
+
+// writeUpstreamError answers a failed upstream call that had no stored data
+// to fall back on: 503 with Retry-After for token problems, while the
+// circuit breaker is open and once the outbound budget is used up, 504 when
+// Fotmob was too slow, 502 otherwise. The raw error is only logged.
+func (s *Server) writeUpstreamError(w http.ResponseWriter, err error) {
+	if errors.Is(err, fotmob.ErrMatchNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, context.Canceled) {
+		w.WriteHeader(statusClientClosedRequest)
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		http.Error(w, "Fotmob did not respond in time", http.StatusGatewayTimeout)
+		return
+	}
+	if errors.Is(err, outbound.ErrBudgetExhausted) {
+		setRetryAfter(w, outbound.ResetIn())
+		http.Error(w, "Today's Fotmob request budget is used up", http.StatusServiceUnavailable)
+		return
+	}
+	if errors.Is(err, fotmob.ErrCircuitOpen) {
+		setRetryAfter(w, s.client.BreakerRetryAfter())
+		http.Error(w, "Fotmob is unavailable; retrying shortly", http.StatusServiceUnavailable)
+		return
+	}
+	if !fotmob.IsTokenError(err) && !s.degraded.active() {
+		http.Error(w, "Fotmob request failed", http.StatusBadGateway)
+		return
+	}
+
+	setRetryAfter(w, s.degraded.retryAfter())
+	http.Error(w, "Fotmob data is temporarily unavailable while the access token is refreshed", http.StatusServiceUnavailable)
+}
+
+// setRetryAfter sets Retry-After in whole seconds
+func setRetryAfter(w http.ResponseWriter, wait time.Duration) {
+	w.Header().Set("Retry-After", fmt.Sprintf("%d", retryAfterSeconds(wait)))
+}
+
+// retryAfterSeconds rounds wait to whole seconds, at least one
+func retryAfterSeconds(wait time.Duration) int {
+	seconds := int(wait.Seconds() + 0.5)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
+
+// health reports "degraded" while we have no usable token or the circuit
+// breaker isn't closed, i.e. whenever clients may get stored data
+func (s *Server) health() map[string]interface{} {
+	status := s.degraded.status()
+	breaker := s.client.Breaker()
+
+	health := "ok"
+	if status.Mode == ModeDegraded || breaker.State != fotmob.BreakerClosed {
+		health = "degraded"
+	}
+	return map[string]interface{}{
+		"status":   health,
+		"degraded": status,
+		"breaker":  breaker,
+	}
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, s.health())
+}
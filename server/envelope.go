@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/fotmob"
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+	"github.com/MichaelBabushkin/sammy_po/pkg/outbound"
+)
+
+// Envelope is the response body of every /api/v1 endpoint. Data is null
+// when Errors is set.
+type Envelope struct {
+	Data   interface{} `json:"data"`
+	Meta   *Meta       `json:"meta,omitempty"`
+	Errors []APIError  `json:"errors,omitempty"`
+}
+
+// Meta describes where the data came from and how fresh it is
+type Meta struct {
+	// Source is the provider that answered ("fotmob" or "feed"), "stored"
+	// for the last stored copy served while no provider is available, or
+	// "static"
+	Source     string      `json:"source"`
+	FetchedAt  *time.Time  `json:"fetchedAt,omitempty"`
+	Stale      bool        `json:"stale"`
+	MaxAge     int         `json:"maxAge"`
+	Locale     i18n.Locale `json:"locale,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+// Pagination describes one page of a list
+type Pagination struct {
+	Page       int `json:"page"`
+	PerPage    int `json:"perPage"`
+	Total      int `json:"total"`
+	TotalPages int `json:"totalPages"`
+}
+
+// APIError is one entry of Envelope.Errors. Codes are stable; messages are
+// for humans and may change.
+type APIError struct {
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	RetryAfter int    `json:"retryAfter,omitempty"`
+}
+
+// Error codes used in v1 responses
+const (
+	CodeInvalidParameter    = "invalid_parameter"
+	CodeNotFound            = "not_found"
+	CodeUnauthorized        = "unauthorized"
+	CodeForbidden           = "forbidden"
+	CodeRateLimited         = "rate_limited"
+	CodeUpstreamUnavailable = "upstream_unavailable"
+	CodeUpstreamError       = "upstream_error"
+	CodeUpstreamTimeout     = "upstream_timeout"
+	CodeRefreshFailed       = "refresh_failed"
+)
+
+// newMeta builds the meta block for data from the data layer
+func newMeta(source dataSource, locale i18n.Locale) *Meta {
+	meta := &Meta{
+		Source: "fotmob",
+		Stale:  source.Stale,
+		MaxAge: int(source.MaxAge.Seconds()),
+		Locale: locale,
+	}
+	if source.Provider != "" {
+		meta.Source = source.Provider
+	}
+	if source.Stale {
+		meta.Source = "stored"
+		meta.MaxAge = 0
+	}
+	if !source.FetchedAt.IsZero() {
+		fetchedAt := source.FetchedAt.UTC().Truncate(time.Second)
+		meta.FetchedAt = &fetchedAt
+	}
+	return meta
+}
+
+// writeEnvelope sends data with its meta block, with the same validators,
+// caching and stale headers as the legacy endpoints
+func (s *Server) writeEnvelope(w http.ResponseWriter, r *http.Request, data interface{}, source dataSource, meta *Meta) {
+	if source.Stale {
+		setStaleHeaders(w, source.FetchedAt)
+		source.MaxAge = 0
+	}
+	s.writeCacheable(w, r, Envelope{Data: data, Meta: meta}, source.FetchedAt, source.MaxAge)
+}
+
+// writeEnvelopeError sends a single error in the v1 envelope
+func writeEnvelopeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, Envelope{Errors: []APIError{{Code: code, Message: message}}})
+}
+
+// writeEnvelopeRetry sends a single error with a Retry-After header
+func writeEnvelopeRetry(w http.ResponseWriter, status int, code, message string, wait time.Duration) {
+	seconds := retryAfterSeconds(wait)
+	w.Header().Set("Retry-After", fmt.Sprintf("%d", seconds))
+	writeJSON(w, status, Envelope{Errors: []APIError{{Code: code, Message: message, RetryAfter: seconds}}})
+}
+
+// writeEnvelopeUpstreamError is writeUpstreamError for v1 endpoints
+func (s *Server) writeEnvelopeUpstreamError(w http.ResponseWriter, err error) {
+	if errors.Is(err, fotmob.ErrMatchNotFound) {
+		writeEnvelopeError(w, http.StatusNotFound, CodeNotFound, err.Error())
+		return
+	}
+	if errors.Is(err, context.Canceled) {
+		w.WriteHeader(statusClientClosedRequest)
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		writeEnvelopeError(w, http.StatusGatewayTimeout, CodeUpstreamTimeout, "Fotmob did not respond in time")
+		return
+	}
+	if errors.Is(err, outbound.ErrBudgetExhausted) {
+		writeEnvelopeRetry(w, http.StatusServiceUnavailable, CodeUpstreamUnavailable,
+			"Today's Fotmob request budget is used up", outbound.ResetIn())
+		return
+	}
+	if errors.Is(err, fotmob.ErrCircuitOpen) {
+		writeEnvelopeRetry(w, http.StatusServiceUnavailable, CodeUpstreamUnavailable,
+			"Fotmob is unavailable; retrying shortly", s.client.BreakerRetryAfter())
+		return
+	}
+	if !fotmob.IsTokenError(err) && !s.degraded.active() {
+		writeEnvelopeError(w, http.StatusBadGateway, CodeUpstreamError, "Fotmob request failed")
+		return
+	}
+	writeEnvelopeRetry(w, http.StatusServiceUnavailable, CodeUpstreamUnavailable,
+		"Fotmob data is temporarily unavailable while the access token is refreshed", s.degraded.retryAfter())
+}
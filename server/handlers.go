@@ -0,0 +1,275 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/api"
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+	"github.com/MichaelBabushkin/sammy_po/pkg/scraper"
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+)
+
+// writeJSON encodes v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// matchIDParam parses the {id} path value
+func matchIDParam(r *http.Request) (int, bool) {
+	matchID, err := strconv.Atoi(r.PathValue("id"))
+	return matchID, err == nil && matchID > 0
+}
+
+// maxConflictWindow bounds the ?window a client may ask conflicts for
+const maxConflictWindow = 7 * 24 * time.Hour
+
+// conflictParams reads the {slug} path value and ?window, which defaults to
+// the configured conflict window
+func (s *Server) conflictParams(r *http.Request) (venue string, window time.Duration, err error) {
+	venue, ok := teams.VenueBySlug(r.PathValue("slug"))
+	if !ok {
+		return "", 0, errUnknownVenue
+	}
+	window = s.conflictWindow()
+	if v := r.URL.Query().Get("window"); v != "" {
+		if window, err = time.ParseDuration(v); err != nil || window <= 0 || window > maxConflictWindow {
+			return "", 0, fmt.Errorf("window must be a duration such as 36h, at most %s", maxConflictWindow)
+		}
+	}
+	return venue, window, nil
+}
+
+var errUnknownVenue = errors.New("unknown venue")
+
+func (s *Server) handleSammyOferMatches(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request for Sammy Ofer matches")
+
+	locale := i18n.FromRequest(r)
+	i18n.SetHeaders(w, locale)
+
+	upcomingMatches, source, err := s.upcomingMatches(r.Context(), locale)
+	if err != nil {
+		s.writeUpstreamError(w, err)
+		return
+	}
+
+	s.writeData(w, r, upcomingMatches, source)
+}
+
+func (s *Server) handleStadiumInfo(w http.ResponseWriter, r *http.Request) {
+	locale := i18n.FromRequest(r)
+	i18n.SetHeaders(w, locale)
+
+	stadiumInfo, source := s.stadiumInfo(locale)
+	s.writeData(w, r, stadiumInfo, source)
+}
+
+func (s *Server) handleVenueConflicts(w http.ResponseWriter, r *http.Request) {
+	venue, window, err := s.conflictParams(r)
+	if err == errUnknownVenue {
+		http.Error(w, "Unknown venue", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	locale := i18n.FromRequest(r)
+	i18n.SetHeaders(w, locale)
+
+	report, source, err := s.venueConflicts(r.Context(), venue, window, locale)
+	if err != nil {
+		s.writeUpstreamError(w, err)
+		return
+	}
+
+	s.writeData(w, r, report, source)
+}
+
+func (s *Server) handleMatchDetails(w http.ResponseWriter, r *http.Request) {
+	matchID, ok := matchIDParam(r)
+	if !ok {
+		http.Error(w, "Invalid match ID", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("Received request for match details: %d", matchID)
+	locale := i18n.FromRequest(r)
+
+	details, source, err := s.matchDetails(r.Context(), matchID)
+	if err != nil {
+		s.writeUpstreamError(w, err)
+		return
+	}
+
+	i18n.SetHeaders(w, locale)
+	s.writeData(w, r, details.Localize(locale), source)
+}
+
+func (s *Server) handleMatchTransit(w http.ResponseWriter, r *http.Request) {
+	matchID, ok := matchIDParam(r)
+	if !ok {
+		http.Error(w, "Invalid match ID", http.StatusBadRequest)
+		return
+	}
+	locale := i18n.FromRequest(r)
+
+	guidance, source, err := s.matchTransit(r.Context(), matchID, locale)
+	switch {
+	case err == errNotAtStadium:
+		http.Error(w, "No upcoming match at Sammy Ofer with this ID", http.StatusNotFound)
+		return
+	case errors.Is(err, errTransitUnavailable):
+		log.Printf("[%s] %v", RequestIDFrom(r.Context()), err)
+		http.Error(w, "Transit suggestions are unavailable", http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		s.writeUpstreamError(w, err)
+		return
+	}
+
+	i18n.SetHeaders(w, locale)
+	s.writeData(w, r, guidance, source)
+}
+
+func (s *Server) handleHeadToHead(w http.ResponseWriter, r *http.Request) {
+	matchID, ok := matchIDParam(r)
+	if !ok {
+		http.Error(w, "Invalid match ID", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("Received request for head-to-head: %d", matchID)
+	locale := i18n.FromRequest(r)
+
+	h2h, source, err := s.headToHead(r.Context(), matchID)
+	if err != nil {
+		s.writeUpstreamError(w, err)
+		return
+	}
+
+	i18n.SetHeaders(w, locale)
+	s.writeData(w, r, h2h.Localize(locale), source)
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	// The spec is embedded, so it only changes when the binary does
+	s.writeValidated(w, r, api.OpenAPISpec, "application/json", startedAt, time.Hour)
+}
+
+func (s *Server) handleRefreshToken(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request to manually refresh token")
+
+	token, source, err := s.manualRefresh(r)
+	if denied, ok := err.(*refreshDenied); ok {
+		writeTooManyRequests(w, denied.reason, denied.wait)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to refresh token: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	// Return success response
+	response := map[string]interface{}{
+		"success":      true,
+		"message":      "Token refreshed successfully",
+		"tokenPreview": scraper.TruncateToken(token),
+		"source":       source,
+		"timestamp":    time.Now().Format(time.RFC3339),
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// refreshDenied is returned by manualRefresh when a refresh isn't allowed yet
+type refreshDenied struct {
+	reason string
+	wait   time.Duration
+}
+
+func (e *refreshDenied) Error() string {
+	return e.reason
+}
+
+// manualRefresh runs a manual token refresh for r, subject to the cooldown,
+// the rate limits and the single-refresh slot. The scrape is abandoned if
+// the client disconnects.
+func (s *Server) manualRefresh(r *http.Request) (token, source string, err error) {
+	if wait := s.refreshCooldownRemaining(); wait > 0 {
+		return "", "", &refreshDenied{"Token was refreshed recently", wait}
+	}
+
+	if ok, wait := s.refreshLimiter.Allow(clientIP(r, s.config.TrustProxy)); !ok {
+		log.Printf("Token refresh rate limit hit by %s", clientIP(r, s.config.TrustProxy))
+		return "", "", &refreshDenied{"Too many token refresh requests", wait}
+	}
+
+	if !s.beginRefresh() {
+		return "", "", &refreshDenied{"A token refresh is already in progress", s.config.RefreshCooldown}
+	}
+	defer s.endRefresh()
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.tokenRefreshTimeout())
+	defer cancel()
+
+	token, source, err = scraper.Refresh(ctx, "manual")
+	if err != nil {
+		log.Printf("All token refresh methods failed: %v", err)
+		return "", "", err
+	}
+
+	s.markRefreshed()
+	return token, source, nil
+}
+
+// refreshCooldownRemaining returns how long until another refresh is allowed.
+// Refreshes triggered by stale-token checks count too, via the token file.
+func (s *Server) refreshCooldownRemaining() time.Duration {
+	s.refreshMu.Lock()
+	last := s.lastRefresh
+	s.refreshMu.Unlock()
+
+	if status, err := api.StoredTokenStatus(); err == nil && status.UpdatedAt.After(last) {
+		last = status.UpdatedAt
+	}
+	if last.IsZero() {
+		return 0
+	}
+	return time.Until(last.Add(s.config.RefreshCooldown))
+}
+
+// beginRefresh claims the refresh slot so only one scrape runs at a time
+func (s *Server) beginRefresh() bool {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	if s.refreshing {
+		return false
+	}
+	s.refreshing = true
+	return true
+}
+
+func (s *Server) endRefresh() {
+	s.refreshMu.Lock()
+	s.refreshing = false
+	s.refreshMu.Unlock()
+}
+
+func (s *Server) markRefreshed() {
+	s.refreshMu.Lock()
+	s.lastRefresh = time.Now()
+	s.refreshMu.Unlock()
+
+	s.degraded.recovered("manual token refresh")
+}
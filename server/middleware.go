@@ -0,0 +1,292 @@
+package server
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// Middleware wraps a handler with extra behaviour
+type Middleware func(http.Handler) http.Handler
+
+// Chain applies middleware so the first one listed is the outermost
+func Chain(h http.Handler, middleware ...Middleware) http.Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+	return h
+}
+
+type contextKey string
+
+const requestIDKey contextKey = "requestID"
+
+// RequestIDFrom returns the request ID assigned by the RequestID middleware
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// RequestID tags each request with an ID, reusing a sane incoming
+// X-Request-ID header, and echoes it on the response
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set("X-Request-ID", id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey, id)))
+	})
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		if !(c == '-' || c == '_' || c == '.' ||
+			(c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')) {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return strings.ReplaceAll(time.Now().Format("150405.000000"), ".", "")
+	}
+	return hex.EncodeToString(b)
+}
+
+// statusRecorder remembers the status code and body size written
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// Logging logs one line per request with status, size and duration
+func Logging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status == 0 {
+			recorder.status = http.StatusOK
+		}
+		log.Printf("[%s] %s %s -> %d (%d bytes) in %v",
+			RequestIDFrom(r.Context()), r.Method, r.URL.RequestURI(),
+			recorder.status, recorder.bytes, time.Since(start))
+	})
+}
+
+// Recovery turns a panicking handler into a 500 instead of a dropped connection
+func Recovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if err := recover(); err != nil {
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+				log.Printf("[%s] panic serving %s: %v\n%s",
+					RequestIDFrom(r.Context()), r.URL.Path, err, debug.Stack())
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CORS adds cross-origin headers for allowed origins and answers preflight
+// requests. Requests from other origins get no CORS headers, so browsers
+// block them.
+func CORS(allowedOrigins []string) Middleware {
+	allowAll := false
+	allowed := make(map[string]bool)
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+		allowed[strings.TrimRight(origin, "/")] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin != "" {
+				w.Header().Add("Vary", "Origin")
+			}
+
+			if origin != "" && (allowAll || allowed[origin]) {
+				if allowAll {
+					w.Header().Set("Access-Control-Allow-Origin", "*")
+				} else {
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+				}
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
+				w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// gzipETagSuffix tells a compressed representation's strong ETag apart
+// from the identity one
+const gzipETagSuffix = "-gzip"
+
+// gzipResponseWriter compresses the body unless the handler already set a
+// Content-Encoding or the response has no body
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz          *gzip.Writer
+	wroteHeader bool
+	compress    bool
+	// revalidated holds the tags a conditional request sent with the
+	// gzip suffix, which a 304 must echo back unchanged
+	revalidated map[string]bool
+}
+
+func (g *gzipResponseWriter) WriteHeader(status int) {
+	if g.wroteHeader {
+		return
+	}
+	g.wroteHeader = true
+
+	h := g.Header()
+	g.compress = h.Get("Content-Encoding") == "" &&
+		status != http.StatusNoContent && status != http.StatusNotModified &&
+		status >= http.StatusOK
+	if etag := h.Get("ETag"); etag != "" && (g.compress || status == http.StatusNotModified && g.revalidated[etag]) {
+		h.Set("ETag", gzipETag(etag))
+	}
+	if g.compress {
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		h.Del("Accept-Ranges")
+	}
+	g.ResponseWriter.WriteHeader(status)
+}
+
+func (g *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !g.wroteHeader {
+		if g.Header().Get("Content-Type") == "" {
+			g.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		g.WriteHeader(http.StatusOK)
+	}
+	if !g.compress {
+		return g.ResponseWriter.Write(b)
+	}
+	if g.gz == nil {
+		g.gz = gzip.NewWriter(g.ResponseWriter)
+	}
+	return g.gz.Write(b)
+}
+
+func (g *gzipResponseWriter) close() {
+	if g.gz != nil {
+		g.gz.Close()
+	}
+}
+
+// Gzip compresses responses for clients that accept it
+func Gzip(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		if !acceptsEncoding(r, "gzip") || r.Method == http.MethodHead || r.Header.Get("Range") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		defer gw.close()
+		if inm := r.Header.Get("If-None-Match"); strings.Contains(inm, gzipETagSuffix) {
+			// Handlers only know the identity tags
+			r = r.Clone(r.Context())
+			r.Header.Set("If-None-Match", gw.identityETags(inm))
+		}
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// gzipETag marks a strong ETag as the compressed variant's; weak ones
+// already allow for a different encoding
+func gzipETag(etag string) string {
+	if len(etag) < 2 || !strings.HasPrefix(etag, `"`) || !strings.HasSuffix(etag, `"`) {
+		return etag
+	}
+	return strings.TrimSuffix(etag, `"`) + gzipETagSuffix + `"`
+}
+
+// identityETags strips the gzip suffix from an If-None-Match list,
+// remembering which tags had it
+func (g *gzipResponseWriter) identityETags(inm string) string {
+	candidates := strings.Split(inm, ",")
+	for i, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		if strings.HasPrefix(candidate, `"`) && strings.HasSuffix(candidate, gzipETagSuffix+`"`) {
+			candidate = strings.TrimSuffix(candidate, gzipETagSuffix+`"`) + `"`
+			if g.revalidated == nil {
+				g.revalidated = make(map[string]bool)
+			}
+			g.revalidated[candidate] = true
+		}
+		candidates[i] = candidate
+	}
+	return strings.Join(candidates, ", ")
+}
+
+// acceptsEncoding reports whether the Accept-Encoding header allows encoding
+func acceptsEncoding(r *http.Request, encoding string) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(fields[0]), encoding) {
+			continue
+		}
+		for _, param := range fields[1:] {
+			if strings.ReplaceAll(strings.TrimSpace(param), " ", "") == "q=0" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/schedule"
+)
+
+// maxRecentChanges is how many schedule changes the admin endpoint keeps
+const maxRecentChanges = 50
+
+// changeNotifier reports schedule changes at Sammy Ofer found in freshly
+// fetched fixtures, with any clashes they create. Changes are logged, kept
+// for the admin endpoint and, when a webhook is configured, posted to it.
+// What was seen before is kept in memory, so the first fetch from each
+// provider after a start is only a baseline.
+type changeNotifier struct {
+	watcher *schedule.Watcher
+	webhook string
+	client  *http.Client
+
+	mu     sync.Mutex
+	recent []schedule.Change
+}
+
+func newChangeNotifier(venue string, window time.Duration, webhook string) *changeNotifier {
+	return &changeNotifier{
+		watcher: schedule.NewWatcher(venue, window),
+		webhook: webhook,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// observe compares fixtures, raw league matches with overrides applied, to
+// the previous fetch from the same provider and sends out the changes
+func (n *changeNotifier) observe(provider string, fixtures []interface{}) {
+	changes := n.watcher.Observe(provider, fixtures)
+	if len(changes) == 0 {
+		return
+	}
+
+	n.mu.Lock()
+	for _, change := range changes {
+		n.recent = append([]schedule.Change{change}, n.recent...)
+	}
+	if len(n.recent) > maxRecentChanges {
+		n.recent = n.recent[:maxRecentChanges]
+	}
+	n.mu.Unlock()
+
+	for _, change := range changes {
+		match := change.Match
+		log.Printf("Schedule change: %s vs %s %s, kickoff %s at %s, %d new conflicts",
+			match.HomeTeam, match.AwayTeam, change.Type, match.Kickoff.Format(time.RFC3339), match.Venue, len(change.Conflicts))
+	}
+	if n.webhook != "" {
+		go n.post(changes)
+	}
+}
+
+// post sends changes to the webhook one at a time, giving up on the first
+// failure
+func (n *changeNotifier) post(changes []schedule.Change) {
+	for _, change := range changes {
+		body, err := json.Marshal(change)
+		if err != nil {
+			log.Printf("Error encoding schedule change for match %d: %v", change.Match.ID, err)
+			continue
+		}
+		resp, err := n.client.Post(n.webhook, "application/json", bytes.NewReader(body))
+		if err != nil {
+			log.Printf("Error posting schedule change to webhook: %v", err)
+			return
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			log.Printf("Schedule change webhook returned %s", resp.Status)
+			return
+		}
+	}
+}
+
+// Recent returns the latest changes, newest first
+func (n *changeNotifier) Recent() []schedule.Change {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]schedule.Change{}, n.recent...)
+}
+
+func (s *Server) handleAdminNotifications(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"notifications": s.changes.Recent(),
+		"webhook":       s.changes.webhook != "",
+	})
+}
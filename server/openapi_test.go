@@ -0,0 +1,220 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/api"
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+)
+
+// The server keeps its token and stored data under responses/ in the
+// working directory, so the tests run in a scratch one
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "sammy-server-test")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	wd, _ := os.Getwd()
+	os.Chdir(dir)
+	os.MkdirAll("responses", 0755)
+	os.WriteFile(filepath.Join("responses", "x-mas-token.txt"), []byte("test-token-0123456789abcdef"), 0644)
+
+	code := m.Run()
+
+	os.Chdir(wd)
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// offline fails every request to Fotmob
+type offline struct{}
+
+func (offline) RoundTrip(r *http.Request) (*http.Response, error) {
+	return nil, errors.New("no network in tests")
+}
+
+// newTestServer serves fixtures from a CSV feed and transit from a small
+// GTFS feed, with the token refresh and Fotmob stubbed out
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	dir := t.TempDir()
+
+	// Kickoffs a week and two weeks ahead, in Israel
+	day := func(days int) string {
+		return time.Now().In(i18n.Jerusalem).AddDate(0, 0, days).Format("2006-01-02")
+	}
+	feed := filepath.Join(dir, "feed.csv")
+	writeFile(t, feed, "Div,Date,Time,HomeTeam,AwayTeam,Venue\n"+
+		"Ligat Ha'Al,"+day(7)+",20:30,Maccabi Haifa,Hapoel Beer Sheva,Sammy Ofer Stadium\n"+
+		"Ligat Ha'Al,"+day(8)+",19:00,Hapoel Haifa,Maccabi Tel Aviv,Sammy Ofer Stadium\n"+
+		"Ligat Ha'Al,"+day(14)+",,Hapoel Haifa,Beitar Jerusalem,Sammy Ofer Stadium\n")
+
+	gtfs := filepath.Join(dir, "gtfs")
+	os.MkdirAll(gtfs, 0755)
+	writeFile(t, filepath.Join(gtfs, "agency.txt"), "agency_id,agency_name,agency_url,agency_timezone\nEGD,Egged,https://egged.co.il,Asia/Jerusalem\n")
+	writeFile(t, filepath.Join(gtfs, "stops.txt"), "stop_id,stop_name,stop_lat,stop_lon\nS1,Sammy Ofer Stadium,32.7840,34.9660\nF1,Merkazit HaMifrats,32.7920,35.0360\n")
+	writeFile(t, filepath.Join(gtfs, "routes.txt"), "route_id,agency_id,route_short_name,route_long_name,route_type\nR114,EGD,114,Merkazit HaMifrats - Hof HaCarmel,3\n")
+	writeFile(t, filepath.Join(gtfs, "calendar.txt"), "service_id,sunday,monday,tuesday,wednesday,thursday,friday,saturday,start_date,end_date\nWK,1,1,1,1,1,1,1,20000101,20991231\n")
+	writeFile(t, filepath.Join(gtfs, "trips.txt"), "route_id,service_id,trip_id,trip_headsign\nR114,WK,A,Hof HaCarmel\nR114,WK,B,Merkazit HaMifrats\n")
+	writeFile(t, filepath.Join(gtfs, "stop_times.txt"), "trip_id,arrival_time,departure_time,stop_id,stop_sequence\n"+
+		"A,17:40:00,17:40:00,F1,1\nA,18:05:00,18:05:00,S1,2\n"+
+		"B,21:00:00,21:00:00,S1,1\nB,21:25:00,21:25:00,F1,2\n")
+
+	config := Config{
+		Port:             "0",
+		Admin:            AdminCredentials{Token: "secret"},
+		RefreshCooldown:  time.Minute,
+		RefreshPerIP:     RateLimit{Burst: 2, Every: time.Minute},
+		RefreshGlobal:    RateLimit{Burst: 5, Every: time.Minute},
+		FixtureProviders: []string{"feed"},
+		FixtureFeed:      feed,
+		OverridesFile:    filepath.Join(dir, "overrides.json"),
+		StadiumFile:      filepath.Join(dir, "stadium.json"),
+		TransitGTFS:      gtfs,
+	}
+	config.FotmobResilience.Retry.MaxAttempts = 1
+
+	s := New(config)
+	s.client.SetTransport(offline{})
+	s.tokenFresh = func() bool { return true }
+	s.tokenStored = func() bool { return true }
+	s.refreshToken = func(ctx context.Context) {}
+	s.degraded = newDegradedState(func() error { return errors.New("no token sources in tests") })
+	return s
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestResponsesMatchSpec calls every documented operation and checks the
+// status and body against the OpenAPI spec
+func TestResponsesMatchSpec(t *testing.T) {
+	spec, err := api.LoadSpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := newTestServer(t).Handler()
+
+	covered := make(map[string]bool)
+	do := func(method, path string, admin bool, body string) (int, []byte) {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if admin {
+			req.Header.Set("Authorization", "Bearer secret")
+		}
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		pathOnly := strings.SplitN(path, "?", 2)[0]
+		if err := spec.ValidateResponse(method, pathOnly, rec.Code, rec.Header().Get("Content-Type"), rec.Body.Bytes()); err != nil {
+			t.Errorf("%s %s: %v", method, path, err)
+		}
+		if template, _, ok := spec.Operation(method, pathOnly); ok {
+			covered[strings.ToLower(method)+" "+template] = true
+		}
+		return rec.Code, rec.Body.Bytes()
+	}
+	get := func(path string) []byte {
+		t.Helper()
+		_, body := do("GET", path, false, "")
+		return body
+	}
+	admin := func(method, path, body string) []byte {
+		t.Helper()
+		_, response := do(method, path, true, body)
+		return response
+	}
+
+	var matches struct {
+		Data []struct {
+			ID int `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(get("/api/v1/matches?lang=he"), &matches); err != nil || len(matches.Data) == 0 {
+		t.Fatalf("no upcoming matches from the feed: %v", err)
+	}
+	id := matches.Data[0].ID
+	tbc := matches.Data[len(matches.Data)-1].ID
+
+	get("/api/v1/health")
+	get("/api/health")
+	get("/api/v1/matches?page=0")
+	get("/api/v1/competitions")
+	get("/api/v1/stadium?lang=he")
+	get("/api/v1/stadium/busy")
+	get("/api/v1/stadium/busy.ics?lang=he")
+	get("/api/v1/venues/sammyofer/conflicts?window=48h")
+	get("/api/v1/venues/nowhere/conflicts")
+	get("/api/venues/sammyofer/conflicts")
+	get("/api/fotmob/sammyofer")
+	get("/api/stadium/sammyofer")
+	get("/api/openapi.json")
+	for _, prefix := range []string{"/api/v1", "/api"} {
+		get(fmt.Sprintf("%s/matches/%d", prefix, id))
+		get(fmt.Sprintf("%s/matches/%d/h2h", prefix, id))
+		get(fmt.Sprintf("%s/matches/%d/transit", prefix, id))
+		get(fmt.Sprintf("%s/matches/%d/transit", prefix, tbc))
+		get(prefix + "/matches/not-a-number")
+		get(prefix + "/matches/999/transit")
+	}
+	get("/schedule?lang=he")
+	get(fmt.Sprintf("/matches/%d", id))
+	get("/stadium")
+
+	// Without credentials, so no scraper runs
+	do("POST", "/api/v1/token/refresh", false, "")
+	do("POST", "/api/refresh-token", false, "")
+	do("GET", "/api/refresh-token", false, "")
+
+	for _, path := range []string{"/api/admin/token", "/api/admin/refreshes", "/api/admin/caches", "/api/admin/upstream", "/api/admin/budget", "/api/admin/notifications"} {
+		admin("GET", path, "")
+	}
+	admin("PUT", "/api/admin/token/source", `{"source": "nowhere"}`)
+	admin("DELETE", "/api/admin/caches/nowhere", "")
+	admin("DELETE", "/api/admin/caches", "")
+
+	kickoff := time.Now().Add(30 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	admin("POST", "/api/admin/overrides", fmt.Sprintf(`{"matchId": %d, "status": "postponed"}`, id))
+	admin("GET", "/api/admin/overrides", "")
+	admin("GET", fmt.Sprintf("/api/admin/overrides/%d", id), "")
+	admin("PUT", fmt.Sprintf("/api/admin/overrides/%d", id), fmt.Sprintf(`{"matchId": %d, "kickoff": %q}`, id, kickoff))
+	admin("GET", "/api/admin/overrides/audit", "")
+	admin("DELETE", fmt.Sprintf("/api/admin/overrides/%d", id), "")
+
+	admin("GET", "/api/admin/stadiums", "")
+	admin("GET", "/api/admin/stadiums/sammyofer", "")
+	admin("PATCH", "/api/admin/stadiums/sammyofer", `{"capacity": 30780}`)
+	admin("PUT", "/api/admin/stadiums/sammyofer", `{"name": "Sammy Ofer Stadium", "version": 2}`)
+	admin("GET", "/api/admin/stadiums/sammyofer/versions", "")
+	admin("GET", "/api/admin/stadiums/sammyofer/versions/1", "")
+	admin("POST", "/api/admin/stadiums/sammyofer/versions/1/restore", "")
+	admin("POST", "/api/admin/stadiums", `{"slug": "bloomfield", "name": "Bloomfield Stadium"}`)
+	admin("DELETE", "/api/admin/stadiums/bloomfield", "")
+	admin("DELETE", "/api/admin/stadiums/sammyofer", "")
+
+	for path, methods := range spec.Paths {
+		for method := range methods {
+			if !covered[method+" "+path] {
+				t.Errorf("%s %s is documented but not exercised", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/overrides"
+)
+
+// overrideRoutes registers the fixture override endpoints behind
+// RequireAdmin
+func (s *Server) overrideRoutes(mux *http.ServeMux) {
+	admin := RequireAdmin(s.config.Admin)
+	handle := func(pattern string, h http.HandlerFunc) {
+		mux.Handle(pattern, admin(h))
+	}
+
+	handle("GET /api/admin/overrides", s.handleListOverrides)
+	handle("POST /api/admin/overrides", s.handleCreateOverride)
+	handle("GET /api/admin/overrides/audit", s.handleOverrideAudit)
+	handle("GET /api/admin/overrides/{id}", s.handleGetOverride)
+	handle("PUT /api/admin/overrides/{id}", s.handleUpdateOverride)
+	handle("DELETE /api/admin/overrides/{id}", s.handleDeleteOverride)
+}
+
+func (s *Server) handleListOverrides(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"overrides": s.overrides.List(),
+	})
+}
+
+func (s *Server) handleOverrideAudit(w http.ResponseWriter, r *http.Request) {
+	matchID := 0
+	if value := r.URL.Query().Get("matchId"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			http.Error(w, "Invalid match ID", http.StatusBadRequest)
+			return
+		}
+		matchID = id
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"audit": s.overrides.Audit(matchID),
+	})
+}
+
+func (s *Server) handleGetOverride(w http.ResponseWriter, r *http.Request) {
+	matchID, ok := overrideID(w, r)
+	if !ok {
+		return
+	}
+	o, found := s.overrides.Get(matchID)
+	if !found {
+		http.Error(w, "Override not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, o)
+}
+
+func (s *Server) handleCreateOverride(w http.ResponseWriter, r *http.Request) {
+	o, ok := decodeOverride(w, r)
+	if !ok {
+		return
+	}
+	created, err := s.overrides.Create(o, adminActor(r))
+	if err != nil {
+		writeOverrideError(w, r, err)
+		return
+	}
+	w.Header().Set("Location", "/api/admin/overrides/"+strconv.Itoa(created.MatchID))
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) handleUpdateOverride(w http.ResponseWriter, r *http.Request) {
+	matchID, ok := overrideID(w, r)
+	if !ok {
+		return
+	}
+	o, ok := decodeOverride(w, r)
+	if !ok {
+		return
+	}
+	updated, err := s.overrides.Update(matchID, o, adminActor(r))
+	if err != nil {
+		writeOverrideError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+func (s *Server) handleDeleteOverride(w http.ResponseWriter, r *http.Request) {
+	matchID, ok := overrideID(w, r)
+	if !ok {
+		return
+	}
+	if err := s.overrides.Delete(matchID, adminActor(r)); err != nil {
+		writeOverrideError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func overrideID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	matchID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || matchID <= 0 {
+		http.Error(w, "Invalid match ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return matchID, true
+}
+
+func decodeOverride(w http.ResponseWriter, r *http.Request) (overrides.Override, bool) {
+	var o overrides.Override
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&o); err != nil {
+		http.Error(w, "Invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return o, false
+	}
+	return o, true
+}
+
+func writeOverrideError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, overrides.ErrInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, overrides.ErrNotFound):
+		http.Error(w, "Override not found", http.StatusNotFound)
+	case errors.Is(err, overrides.ErrExists):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("[%s] Error saving fixture override: %v", RequestIDFrom(r.Context()), err)
+		http.Error(w, "Failed to save override", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,241 @@
+package server
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/fotmob"
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+)
+
+// startedAt stands in as the modification time of pages built from static data
+var startedAt = time.Now()
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var templateFuncs = template.FuncMap{
+	"t": func(locale i18n.Locale, key string) string {
+		return i18n.T(locale, key)
+	},
+	"lineups": func(l fotmob.MatchLineups) []*fotmob.TeamLineup {
+		var lineups []*fotmob.TeamLineup
+		for _, lineup := range []*fotmob.TeamLineup{l.Home, l.Away} {
+			if lineup != nil {
+				lineups = append(lineups, lineup)
+			}
+		}
+		return lineups
+	},
+}
+
+// pageTemplates holds one template set per page, each combined with the
+// shared layout
+var pageTemplates = func() map[string]*template.Template {
+	pages := make(map[string]*template.Template)
+	for _, page := range []string{"schedule", "match", "stadium"} {
+		pages[page] = template.Must(template.New(page).Funcs(templateFuncs).
+			ParseFS(templateFS, "templates/layout.html", "templates/"+page+".html"))
+	}
+	return pages
+}()
+
+// openGraph is the link preview metadata for a page
+type openGraph struct {
+	Type        string
+	Title       string
+	Description string
+	URL         string
+	Image       string
+}
+
+// pageData is what every page template receives
+type pageData struct {
+	Locale      i18n.Locale
+	OtherLocale i18n.Locale
+	Dir         string
+	Title       string
+	Heading     string
+	BaseURL     string
+	Path        string
+	OG          openGraph
+	Stale       bool
+	FetchedAt   string
+	Content     interface{}
+}
+
+// scheduleItem is one row of the schedule page
+type scheduleItem struct {
+	ID          int
+	Home        string
+	Away        string
+	HomeLogo    string
+	AwayLogo    string
+	Date        string
+	Time        string
+	TimeTBC     bool
+	Competition string
+	Round       string
+}
+
+// matchPage is the content of the match detail page
+type matchPage struct {
+	Details    *fotmob.MatchDetails
+	HeadToHead *fotmob.HeadToHead
+}
+
+// newPageData fills in the fields shared by every page
+func (s *Server) newPageData(r *http.Request, locale i18n.Locale, title string) *pageData {
+	data := &pageData{
+		Locale:      locale,
+		OtherLocale: i18n.Hebrew,
+		Dir:         "ltr",
+		Title:       title,
+		Heading:     title,
+		BaseURL:     s.baseURL(r),
+		Path:        r.URL.Path,
+	}
+	if locale == i18n.Hebrew {
+		data.OtherLocale = i18n.English
+		data.Dir = "rtl"
+	}
+
+	data.OG = openGraph{
+		Type:        "website",
+		Title:       title,
+		Description: s.sammyOfer().Info(locale).Description,
+		URL:         fmt.Sprintf("%s%s?lang=%s", data.BaseURL, data.Path, locale),
+		Image:       s.sammyOfer().ImageURL,
+	}
+	return data
+}
+
+// baseURL is the public origin used in canonical and Open Graph links
+func (s *Server) baseURL(r *http.Request) string {
+	if s.config.PublicURL != "" {
+		return strings.TrimRight(s.config.PublicURL, "/")
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	} else if proto := r.Header.Get("X-Forwarded-Proto"); s.config.TrustProxy && proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
+
+// renderPage executes a page template and sends it with validators
+func (s *Server) renderPage(w http.ResponseWriter, r *http.Request, page string, data *pageData, source dataSource) {
+	if source.Stale {
+		setStaleHeaders(w, source.FetchedAt)
+		data.Stale = true
+		data.FetchedAt = i18n.FormatDate(data.Locale, source.FetchedAt) + " " + i18n.FormatTime(data.Locale, source.FetchedAt)
+		source.MaxAge = 0
+	}
+
+	var body bytes.Buffer
+	if err := pageTemplates[page].ExecuteTemplate(&body, "layout", data); err != nil {
+		log.Printf("Error rendering %s page: %v", page, err)
+		http.Error(w, "Error rendering page", http.StatusInternalServerError)
+		return
+	}
+
+	i18n.SetHeaders(w, data.Locale)
+	s.writeValidated(w, r, body.Bytes(), "text/html; charset=utf-8", source.FetchedAt, source.MaxAge)
+}
+
+func (s *Server) handleSchedulePage(w http.ResponseWriter, r *http.Request) {
+	locale := i18n.FromRequest(r)
+
+	matches, source, err := s.upcomingMatches(r.Context(), locale)
+	if err != nil {
+		s.writeUpstreamError(w, err)
+		return
+	}
+
+	items := make([]scheduleItem, 0, len(matches))
+	for _, raw := range matches {
+		matchMap, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		match, _ := matchMap["match"].(fotmob.Match)
+		localized, _ := matchMap["localized"].(fotmob.LocalizedMatch)
+		items = append(items, scheduleItem{
+			ID:          match.ID,
+			Home:        localized.HomeTeam,
+			Away:        localized.AwayTeam,
+			HomeLogo:    match.HomeTeamLogo,
+			AwayLogo:    match.AwayTeamLogo,
+			Date:        match.Date,
+			Time:        match.Time,
+			TimeTBC:     match.TimeTBC,
+			Competition: localized.Competition,
+			Round:       match.Round,
+		})
+	}
+
+	data := s.newPageData(r, locale, i18n.T(locale, "page.schedule.title"))
+	data.Content = items
+	s.renderPage(w, r, "schedule", data, source)
+}
+
+func (s *Server) handleMatchPage(w http.ResponseWriter, r *http.Request) {
+	matchID, ok := matchIDParam(r)
+	if !ok {
+		http.Error(w, "Invalid match ID", http.StatusBadRequest)
+		return
+	}
+	locale := i18n.FromRequest(r)
+
+	details, source, err := s.matchDetails(r.Context(), matchID)
+	if err != nil {
+		s.writeUpstreamError(w, err)
+		return
+	}
+	details = details.Localize(locale)
+	content := matchPage{Details: details}
+
+	// Head-to-head is a nice extra; the page works without it
+	if h2h, _, err := s.headToHead(r.Context(), matchID); err == nil {
+		content.HeadToHead = h2h.Localize(locale)
+	}
+
+	title := fmt.Sprintf("%s – %s", details.Localized.HomeTeam, details.Localized.AwayTeam)
+	data := s.newPageData(r, locale, title)
+	data.Content = content
+
+	description := []string{}
+	for _, part := range []string{details.Localized.Competition, strings.TrimSpace(details.Localized.Date + " " + details.Localized.Time)} {
+		if part != "" {
+			description = append(description, part)
+		}
+	}
+	if details.Venue != nil && details.Venue.Name != "" {
+		description = append(description, details.Venue.Name)
+	}
+	data.OG.Type = "article"
+	data.OG.Title = title
+	data.OG.Description = strings.Join(description, " · ")
+	if details.HomeTeam.LogoURL != "" {
+		data.OG.Image = details.HomeTeam.LogoURL
+	}
+
+	s.renderPage(w, r, "match", data, source)
+}
+
+func (s *Server) handleStadiumPage(w http.ResponseWriter, r *http.Request) {
+	locale := i18n.FromRequest(r)
+	info, source := s.stadiumInfo(locale)
+
+	data := s.newPageData(r, locale, info.Name)
+	data.Content = info
+	s.renderPage(w, r, "stadium", data, source)
+}
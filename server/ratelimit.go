@@ -0,0 +1,170 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenBucket allows bursts of up to capacity events, refilling at rate
+// tokens per second
+type tokenBucket struct {
+	capacity float64
+	rate     float64
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(capacity int, every time.Duration, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(capacity),
+		rate:     1 / every.Seconds(),
+		tokens:   float64(capacity),
+		last:     now,
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.capacity, b.tokens+elapsed*b.rate)
+	}
+	b.last = now
+}
+
+// take consumes a token if one is available. Otherwise it returns how long
+// until the next token.
+func (b *tokenBucket) take(now time.Time) (bool, time.Duration) {
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := (1 - b.tokens) / b.rate
+	return false, time.Duration(math.Ceil(wait)) * time.Second
+}
+
+// full reports whether the bucket has refilled completely
+func (b *tokenBucket) full(now time.Time) bool {
+	b.refill(now)
+	return b.tokens >= b.capacity
+}
+
+// RateLimit configures a limiter: Burst events at once, then one per Every
+type RateLimit struct {
+	Burst int
+	Every time.Duration
+}
+
+// RateLimiter applies a per-client and a global token bucket
+type RateLimiter struct {
+	mu       sync.Mutex
+	perIP    RateLimit
+	buckets  map[string]*tokenBucket
+	global   *tokenBucket
+	lastTidy time.Time
+	now      func() time.Time
+}
+
+// NewRateLimiter creates a limiter with separate per-IP and global limits
+func NewRateLimiter(perIP, global RateLimit) *RateLimiter {
+	now := time.Now()
+	return &RateLimiter{
+		perIP:    perIP,
+		buckets:  make(map[string]*tokenBucket),
+		global:   newTokenBucket(global.Burst, global.Every, now),
+		lastTidy: now,
+		now:      time.Now,
+	}
+}
+
+// Allow records an attempt by ip. When it is refused it also returns how
+// long the caller should wait.
+func (l *RateLimiter) Allow(ip string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.tidy(now)
+
+	bucket, ok := l.buckets[ip]
+	if !ok {
+		bucket = newTokenBucket(l.perIP.Burst, l.perIP.Every, now)
+		l.buckets[ip] = bucket
+	}
+
+	// Check the client first so one noisy client can't drain the global budget
+	if ok, wait := bucket.take(now); !ok {
+		return false, wait
+	}
+	if ok, wait := l.global.take(now); !ok {
+		// Give the client its token back; the refusal wasn't its fault
+		bucket.tokens++
+		return false, wait
+	}
+	return true, 0
+}
+
+// AllowGlobal records an attempt that has no client, such as a refresh
+// started by the server itself, against the global limit only
+func (l *RateLimiter) AllowGlobal() (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.global.take(l.now())
+}
+
+// tidy drops buckets of clients that have been idle long enough to refill
+func (l *RateLimiter) tidy(now time.Time) {
+	if now.Sub(l.lastTidy) < time.Minute {
+		return
+	}
+	l.lastTidy = now
+	for ip, bucket := range l.buckets {
+		if bucket.full(now) {
+			delete(l.buckets, ip)
+		}
+	}
+}
+
+// clientIP returns the caller's address. X-Forwarded-For is only honoured
+// when the server runs behind a trusted proxy, and then only its last
+// entry, the one that proxy appended: earlier entries come from the client
+// and can be forged.
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		forwarded := r.Header.Values("X-Forwarded-For")
+		if len(forwarded) > 0 {
+			entries := strings.Split(forwarded[len(forwarded)-1], ",")
+			if ip := strings.TrimSpace(entries[len(entries)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// writeTooManyRequests sends a 429 with Retry-After and the time the next
+// attempt will be accepted
+func writeTooManyRequests(w http.ResponseWriter, message string, wait time.Duration) {
+	if wait < time.Second {
+		wait = time.Second
+	}
+	seconds := int(math.Ceil(wait.Seconds()))
+	next := time.Now().Add(time.Duration(seconds) * time.Second)
+
+	w.Header().Set("Retry-After", fmt.Sprintf("%d", seconds))
+	writeJSON(w, http.StatusTooManyRequests, map[string]interface{}{
+		"success":       false,
+		"message":       message,
+		"retryAfter":    seconds,
+		"nextAllowedAt": next.UTC().Format(time.RFC3339),
+	})
+}
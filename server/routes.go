@@ -0,0 +1,71 @@
+package server
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/MichaelBabushkin/sammy_po/frontend"
+)
+
+// routes registers every endpoint using method-aware ServeMux patterns
+func (s *Server) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+
+	// Data mode, for load balancers and monitoring
+	mux.HandleFunc("GET /api/health", s.handleHealth)
+
+	// Versioned API with stable envelopes
+	s.v1Routes(mux)
+
+	// Legacy unversioned routes, kept until legacySunset
+	legacy := func(successor string, h http.Handler) http.Handler {
+		return Deprecated(successor)(h)
+	}
+
+	// Upcoming matches at Sammy Ofer
+	mux.Handle("GET /api/fotmob/sammyofer", legacy("/api/v1/matches", http.HandlerFunc(s.handleSammyOferMatches)))
+
+	// Stadium info
+	mux.Handle("GET /api/stadium/sammyofer", legacy("/api/v1/stadium", http.HandlerFunc(s.handleStadiumInfo)))
+
+	// Match details and head-to-head history
+	mux.Handle("GET /api/matches/{id}", legacy("/api/v1/matches/{id}", http.HandlerFunc(s.handleMatchDetails)))
+	mux.Handle("GET /api/matches/{id}/h2h", legacy("/api/v1/matches/{id}/h2h", http.HandlerFunc(s.handleHeadToHead)))
+
+	// Public transport to and from a match at Sammy Ofer
+	mux.HandleFunc("GET /api/matches/{id}/transit", s.handleMatchTransit)
+
+	// Fixtures at a venue that clash
+	mux.HandleFunc("GET /api/venues/{slug}/conflicts", s.handleVenueConflicts)
+
+	// OpenAPI description of this API
+	mux.HandleFunc("GET /api/openapi.json", s.handleOpenAPI)
+
+	// Manual token refresh (admin only)
+	admin := RequireAdmin(s.config.Admin)
+	mux.Handle("GET /api/refresh-token", legacy("/api/v1/token/refresh", admin(http.HandlerFunc(s.handleRefreshToken))))
+	mux.Handle("POST /api/refresh-token", legacy("/api/v1/token/refresh", admin(http.HandlerFunc(s.handleRefreshToken))))
+
+	// Admin dashboard data
+	s.adminRoutes(mux)
+
+	// Fixture corrections (admin only)
+	s.overrideRoutes(mux)
+
+	// Stadium profiles (admin only)
+	s.stadiumRoutes(mux)
+
+	// Server-rendered pages that work without JavaScript
+	mux.HandleFunc("GET /schedule", s.handleSchedulePage)
+	mux.HandleFunc("GET /matches/{id}", s.handleMatchPage)
+	mux.HandleFunc("GET /stadium", s.handleStadiumPage)
+
+	// The React app, embedded in the binary unless serving from disk
+	if s.config.StaticDir != "" {
+		mux.Handle("GET /", newStaticHandler(os.DirFS(s.config.StaticDir), true))
+	} else {
+		mux.Handle("GET /", newStaticHandler(frontend.Build(), false))
+	}
+
+	return mux
+}
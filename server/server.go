@@ -0,0 +1,488 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/api"
+	"github.com/MichaelBabushkin/sammy_po/pkg/fotmob"
+	"github.com/MichaelBabushkin/sammy_po/pkg/outbound"
+	"github.com/MichaelBabushkin/sammy_po/pkg/overrides"
+	"github.com/MichaelBabushkin/sammy_po/pkg/provider"
+	"github.com/MichaelBabushkin/sammy_po/pkg/schedule"
+	"github.com/MichaelBabushkin/sammy_po/pkg/scraper"
+	"github.com/MichaelBabushkin/sammy_po/pkg/stadium"
+	"github.com/MichaelBabushkin/sammy_po/pkg/teams"
+	"github.com/MichaelBabushkin/sammy_po/pkg/transit"
+)
+
+// Config holds the server settings, usually loaded from the environment
+type Config struct {
+	// Port to listen on
+	Port string
+	// AllowedOrigins lists origins allowed to make cross-origin requests.
+	// "*" allows any origin; empty means same-origin only.
+	AllowedOrigins []string
+	// StaticDir serves the frontend from this directory instead of the build
+	// embedded in the binary, for development
+	StaticDir string
+	// PublicURL is the site's public origin for canonical and Open Graph
+	// links; when empty it is derived from each request
+	PublicURL string
+
+	// Admin guards the admin endpoints such as the token refresh
+	Admin AdminCredentials
+	// TrustProxy uses X-Forwarded-For as the client address for rate limiting
+	TrustProxy bool
+	// RefreshCooldown is the minimum time between two token refreshes
+	RefreshCooldown time.Duration
+	// RefreshPerIP and RefreshGlobal limit how often refreshes may be triggered
+	RefreshPerIP  RateLimit
+	RefreshGlobal RateLimit
+
+	// FotmobTimeouts are per-call deadlines for upstream requests, on top
+	// of the incoming request's context
+	FotmobTimeouts fotmob.Timeouts
+	// FotmobResilience sets how failed upstream requests are retried and
+	// when the circuit breaker opens
+	FotmobResilience fotmob.Resilience
+	// Outbound limits requests to fotmob.com from the whole process,
+	// including the token scrapers
+	Outbound outbound.Config
+	// TokenRefreshTimeout bounds a whole token refresh across all sources
+	TokenRefreshTimeout time.Duration
+
+	// FixtureProviders lists "fotmob" and "feed" in the order they are
+	// tried; later ones are only asked when earlier ones fail
+	FixtureProviders []string
+	// FixtureFeed is the path or URL of a football-data style JSON or CSV
+	// feed for the "feed" provider
+	FixtureFeed string
+	// FixtureFeedTTL is how long a feed fetched from a URL is kept
+	FixtureFeedTTL time.Duration
+
+	// OverridesFile stores the admins' fixture overrides and their audit
+	// trail
+	OverridesFile string
+
+	// VenueLookahead is how far ahead fixtures have their match details
+	// looked up in the background to learn their venue, and VenueLookupBatch
+	// how many at a time; zero turns the lookups off
+	VenueLookahead   time.Duration
+	VenueLookupBatch int
+
+	// ConflictWindow is how close two fixtures at one venue may be before
+	// they are flagged as a conflict
+	ConflictWindow time.Duration
+	// NotifyWebhook receives a JSON POST for each schedule change at Sammy
+	// Ofer
+	NotifyWebhook string
+	// OccupancyFile is a JSON occupancy model adjusting the default busy
+	// window offsets and crowd estimates
+	OccupancyFile string
+
+	// StadiumFile stores the editable stadium profiles and their versions
+	StadiumFile string
+
+	// TransitGTFS is a GTFS static feed, zipped or unpacked, for public
+	// transport suggestions; TransitWalkRadius is how many meters from the
+	// stadium a stop may be
+	TransitGTFS       string
+	TransitWalkRadius int
+}
+
+// ConfigFromEnv reads PORT, CORS_ALLOWED_ORIGINS (comma separated),
+// FRONTEND_DIR, PUBLIC_URL, ADMIN_TOKEN, ADMIN_USER, ADMIN_PASSWORD,
+// TRUST_PROXY, REFRESH_COOLDOWN, the FOTMOB_*_TIMEOUT deadlines,
+// FOTMOB_RETRY_*, FOTMOB_BREAKER_*, the OUTBOUND_* limits,
+// TOKEN_REFRESH_TIMEOUT, FIXTURE_PROVIDERS (comma separated), FIXTURE_FEED,
+// FIXTURE_FEED_TTL, OVERRIDES_FILE, VENUE_LOOKAHEAD, VENUE_LOOKUP_BATCH,
+// CONFLICT_WINDOW, NOTIFY_WEBHOOK, OCCUPANCY_FILE, STADIUM_FILE,
+// TRANSIT_GTFS and TRANSIT_WALK_RADIUS
+func ConfigFromEnv() Config {
+	config := Config{
+		Port:      os.Getenv("PORT"),
+		StaticDir: os.Getenv("FRONTEND_DIR"),
+		PublicURL: os.Getenv("PUBLIC_URL"),
+		Admin: AdminCredentials{
+			Token:    os.Getenv("ADMIN_TOKEN"),
+			User:     os.Getenv("ADMIN_USER"),
+			Password: os.Getenv("ADMIN_PASSWORD"),
+		},
+		RefreshCooldown: 2 * time.Minute,
+		RefreshPerIP:    RateLimit{Burst: 2, Every: 10 * time.Minute},
+		RefreshGlobal:   RateLimit{Burst: 5, Every: 5 * time.Minute},
+
+		FotmobTimeouts:      fotmob.DefaultTimeouts,
+		FotmobResilience:    fotmob.DefaultResilience,
+		Outbound:            outbound.DefaultConfig,
+		TokenRefreshTimeout: 90 * time.Second,
+
+		FixtureFeed:    os.Getenv("FIXTURE_FEED"),
+		FixtureFeedTTL: 5 * time.Minute,
+
+		OverridesFile: os.Getenv("OVERRIDES_FILE"),
+
+		VenueLookahead:   21 * 24 * time.Hour,
+		VenueLookupBatch: 10,
+
+		ConflictWindow: schedule.DefaultWindow,
+		NotifyWebhook:  os.Getenv("NOTIFY_WEBHOOK"),
+		OccupancyFile:  os.Getenv("OCCUPANCY_FILE"),
+
+		StadiumFile: os.Getenv("STADIUM_FILE"),
+
+		TransitGTFS:       os.Getenv("TRANSIT_GTFS"),
+		TransitWalkRadius: transit.DefaultRadius,
+	}
+	if config.Port == "" {
+		config.Port = "8000"
+	}
+	if config.OverridesFile == "" {
+		config.OverridesFile = filepath.Join("responses", "overrides.json")
+	}
+	if config.StadiumFile == "" {
+		config.StadiumFile = filepath.Join("responses", "stadium.json")
+	}
+
+	config.TrustProxy, _ = strconv.ParseBool(os.Getenv("TRUST_PROXY"))
+
+	durationFromEnv("REFRESH_COOLDOWN", &config.RefreshCooldown, true)
+	durationFromEnv("FOTMOB_LEAGUE_TIMEOUT", &config.FotmobTimeouts.League, false)
+	durationFromEnv("FOTMOB_SEASON_TIMEOUT", &config.FotmobTimeouts.Season, false)
+	durationFromEnv("FOTMOB_MATCH_TIMEOUT", &config.FotmobTimeouts.MatchDetails, false)
+	durationFromEnv("TOKEN_REFRESH_TIMEOUT", &config.TokenRefreshTimeout, false)
+	intFromEnv("FOTMOB_RETRY_ATTEMPTS", &config.FotmobResilience.Retry.MaxAttempts, false)
+	durationFromEnv("FOTMOB_RETRY_BASE_DELAY", &config.FotmobResilience.Retry.BaseDelay, false)
+	durationFromEnv("FOTMOB_RETRY_MAX_DELAY", &config.FotmobResilience.Retry.MaxDelay, false)
+	intFromEnv("FOTMOB_BREAKER_THRESHOLD", &config.FotmobResilience.Breaker.Threshold, false)
+	durationFromEnv("FOTMOB_BREAKER_COOLDOWN", &config.FotmobResilience.Breaker.Cooldown, false)
+	intFromEnv("OUTBOUND_BURST", &config.Outbound.Burst, false)
+	durationFromEnv("OUTBOUND_EVERY", &config.Outbound.Every, false)
+	intFromEnv("OUTBOUND_DAILY_BUDGET", &config.Outbound.DailyBudget, true)
+	if value := os.Getenv("OUTBOUND_BACKGROUND_SHARE"); value != "" {
+		share, err := strconv.ParseFloat(value, 64)
+		if err != nil || share <= 0 || share > 1 {
+			log.Printf("Ignoring invalid OUTBOUND_BACKGROUND_SHARE %q", value)
+		} else {
+			config.Outbound.BackgroundShare = share
+		}
+	}
+
+	durationFromEnv("FIXTURE_FEED_TTL", &config.FixtureFeedTTL, false)
+	durationFromEnv("VENUE_LOOKAHEAD", &config.VenueLookahead, true)
+	intFromEnv("VENUE_LOOKUP_BATCH", &config.VenueLookupBatch, true)
+	durationFromEnv("CONFLICT_WINDOW", &config.ConflictWindow, false)
+	intFromEnv("TRANSIT_WALK_RADIUS", &config.TransitWalkRadius, false)
+
+	// With a feed configured it backs Fotmob up unless told otherwise
+	config.FixtureProviders = []string{"fotmob"}
+	if config.FixtureFeed != "" {
+		config.FixtureProviders = append(config.FixtureProviders, "feed")
+	}
+	if value := os.Getenv("FIXTURE_PROVIDERS"); value != "" {
+		config.FixtureProviders = nil
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				config.FixtureProviders = append(config.FixtureProviders, name)
+			}
+		}
+	}
+
+	for _, origin := range strings.Split(os.Getenv("CORS_ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			config.AllowedOrigins = append(config.AllowedOrigins, origin)
+		}
+	}
+
+	return config
+}
+
+// durationFromEnv overrides *target with the environment variable name when
+// it holds a valid duration, such as "30s"
+func durationFromEnv(name string, target *time.Duration, allowZero bool) {
+	value := os.Getenv(name)
+	if value == "" {
+		return
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 || (d == 0 && !allowZero) {
+		log.Printf("Ignoring invalid %s %q", name, value)
+		return
+	}
+	*target = d
+}
+
+// intFromEnv overrides *target with the environment variable name when it
+// holds a positive integer, or zero if allowZero
+func intFromEnv(name string, target *int, allowZero bool) {
+	value := os.Getenv(name)
+	if value == "" {
+		return
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 || (n == 0 && !allowZero) {
+		log.Printf("Ignoring invalid %s %q", name, value)
+		return
+	}
+	*target = n
+}
+
+// Server wires the HTTP handlers to their dependencies. The token functions
+// default to the scraper package and can be replaced in tests.
+type Server struct {
+	config    Config
+	client    *fotmob.FotmobClient
+	fixtures  *provider.Chain
+	overrides *overrides.Store
+	venues    *venueLookups
+	changes   *changeNotifier
+	occupancy schedule.Occupancy
+	stadiums  *stadium.Store
+	transit   *transit.Feed
+	handler   http.Handler
+
+	tokenFresh   func() bool
+	tokenStored  func() bool
+	refreshToken func(ctx context.Context)
+	degraded     *degradedState
+	validators   *validatorMemo
+
+	// Refresh guards, shared by manual and stale-token refreshes
+	refreshLimiter *RateLimiter
+	refreshMu      sync.Mutex
+	refreshing     bool
+	lastRefresh    time.Time
+	// staleRefresh is closed when the running stale-token refresh ends
+	staleRefresh chan struct{}
+}
+
+// New creates a server with its routes and middleware chain
+func New(config Config) *Server {
+	outbound.Configure(config.Outbound)
+
+	s := &Server{
+		config:       config,
+		client:       fotmob.NewFotmobClientWithResilience(config.FotmobTimeouts, config.FotmobResilience),
+		tokenFresh:   scraper.IsTokenFresh,
+		tokenStored:  hasStoredToken,
+		refreshToken: scraper.RefreshToken,
+
+		refreshLimiter: NewRateLimiter(config.RefreshPerIP, config.RefreshGlobal),
+		validators:     newValidatorMemo(),
+	}
+	s.degraded = newDegradedState(func() error {
+		// Background refreshes outlive the request that triggered them and
+		// yield to user requests for the outbound budget
+		ctx := outbound.WithPriority(context.Background(), outbound.Background)
+		ctx, cancel := context.WithTimeout(ctx, s.tokenRefreshTimeout())
+		defer cancel()
+		_, _, err := scraper.Refresh(ctx, "degraded")
+		return err
+	})
+
+	s.fixtures = s.fixtureProviders()
+
+	store, err := overrides.Open(config.OverridesFile)
+	if err != nil {
+		log.Printf("Warning: Fixture overrides can't be changed until this is fixed: %v", err)
+	}
+	s.overrides = store
+	s.venues = newVenueLookups(config.VenueLookahead, config.VenueLookupBatch, s.client)
+	s.changes = newChangeNotifier(teams.SammyOferStadium, s.conflictWindow(), config.NotifyWebhook)
+
+	s.occupancy = schedule.DefaultOccupancy
+	if config.OccupancyFile != "" {
+		if s.occupancy, err = schedule.LoadOccupancy(config.OccupancyFile); err != nil {
+			log.Printf("Warning: Using the default occupancy model: %v", err)
+		}
+	}
+
+	stadiums, err := stadium.Open(config.StadiumFile)
+	if err != nil {
+		log.Printf("Warning: Stadium profiles can't be changed until this is fixed: %v", err)
+	}
+	s.stadiums = stadiums
+
+	if config.TransitGTFS != "" {
+		s.transit = transit.NewFeed(config.TransitGTFS, float64(config.TransitWalkRadius))
+		// Reading a national feed takes a while, so don't leave it to the
+		// first request
+		go func() {
+			if center, ok := s.stadiumCenter(); ok {
+				if err := s.transit.Load(center); err != nil {
+					log.Printf("Warning: Transit suggestions are unavailable: %v", err)
+				}
+			}
+		}()
+	}
+
+	if !config.Admin.Configured() {
+		log.Println("No admin credentials configured; admin endpoints are disabled")
+	}
+
+	s.handler = Chain(s.routes(),
+		RequestID,
+		Logging,
+		Recovery,
+		CORS(config.AllowedOrigins),
+		Gzip,
+	)
+
+	return s
+}
+
+// fixtureProviders builds the provider chain from the config. Fotmob calls
+// feed the degraded-mode state machine wherever they happen in the chain.
+func (s *Server) fixtureProviders() *provider.Chain {
+	var providers []provider.FixtureProvider
+	for _, name := range s.config.FixtureProviders {
+		switch name {
+		case "fotmob":
+			providers = append(providers, provider.NewFotmob(s.client))
+		case "feed":
+			if s.config.FixtureFeed == "" {
+				log.Println("Ignoring the feed fixture provider: FIXTURE_FEED is not set")
+				continue
+			}
+			providers = append(providers, provider.NewFeed(s.config.FixtureFeed, s.config.FixtureFeedTTL))
+		default:
+			log.Printf("Ignoring unknown fixture provider %q", name)
+		}
+	}
+	if len(providers) == 0 {
+		providers = append(providers, provider.NewFotmob(s.client))
+	}
+
+	chain := provider.NewChain(providers...)
+	chain.Observe = func(name string, err error) {
+		if name != "fotmob" {
+			return
+		}
+		// Fotmob not knowing a match is still an answer
+		if err == nil || errors.Is(err, fotmob.ErrMatchNotFound) {
+			s.degraded.recovered("upstream request succeeded")
+			return
+		}
+		s.degraded.observe(err)
+	}
+	log.Printf("Fixture providers: %s", chain.Name())
+	return chain
+}
+
+// Handler returns the root handler including all middleware
+func (s *Server) Handler() http.Handler {
+	return s.handler
+}
+
+// ListenAndServe serves the API and frontend on the configured port
+func (s *Server) ListenAndServe() error {
+	log.Printf("Starting server on :%s...", s.config.Port)
+	return http.ListenAndServe(":"+s.config.Port, s.handler)
+}
+
+// ensureFreshToken refreshes the Fotmob token before an upstream call if
+// the stored one is stale. The refresh runs in the background, one at a
+// time and subject to the cooldown and global refresh limit, while
+// requests carry on with the current token or stored data. Only when there
+// is no token at all does a request wait for it. In degraded mode the
+// degraded-mode refresh is used instead.
+func (s *Server) ensureFreshToken(ctx context.Context) {
+	if s.degraded.active() {
+		s.degraded.triggerRefresh()
+		return
+	}
+	if s.tokenFresh() {
+		return
+	}
+	done := s.startStaleRefresh()
+	if done == nil || s.tokenStored() {
+		return
+	}
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// startStaleRefresh starts a background refresh of a stale token, or joins
+// the one running, returning a channel closed when it ends. It returns nil
+// when the cooldown or the global limit doesn't allow a refresh, or a
+// manual refresh holds the slot.
+func (s *Server) startStaleRefresh() <-chan struct{} {
+	s.refreshMu.Lock()
+	if s.staleRefresh != nil {
+		done := s.staleRefresh
+		s.refreshMu.Unlock()
+		return done
+	}
+	s.refreshMu.Unlock()
+
+	if s.refreshCooldownRemaining() > 0 {
+		return nil
+	}
+	if ok, _ := s.refreshLimiter.AllowGlobal(); !ok {
+		log.Println("Stale token refresh skipped: global refresh limit reached")
+		return nil
+	}
+
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	if s.staleRefresh != nil {
+		return s.staleRefresh
+	}
+	if s.refreshing {
+		return nil
+	}
+	s.refreshing = true
+	done := make(chan struct{})
+	s.staleRefresh = done
+
+	go func() {
+		log.Println("Token is stale, refreshing in the background...")
+		// Nobody waits on this refresh, so it yields to user requests for
+		// the outbound budget
+		ctx := outbound.WithPriority(context.Background(), outbound.Background)
+		ctx, cancel := context.WithTimeout(ctx, s.tokenRefreshTimeout())
+		defer cancel()
+		s.refreshToken(ctx)
+
+		// A failed refresh counts towards the cooldown too, so a broken
+		// scraper isn't restarted on every request
+		s.refreshMu.Lock()
+		s.refreshing = false
+		s.lastRefresh = time.Now()
+		s.staleRefresh = nil
+		s.refreshMu.Unlock()
+		close(done)
+	}()
+	return done
+}
+
+// hasStoredToken reports whether a refresh has saved a token, stale or not
+func hasStoredToken() bool {
+	status, err := api.StoredTokenStatus()
+	return err == nil && status.Token != ""
+}
+
+func (s *Server) conflictWindow() time.Duration {
+	if s.config.ConflictWindow > 0 {
+		return s.config.ConflictWindow
+	}
+	return schedule.DefaultWindow
+}
+
+func (s *Server) tokenRefreshTimeout() time.Duration {
+	if s.config.TokenRefreshTimeout > 0 {
+		return s.config.TokenRefreshTimeout
+	}
+	return 90 * time.Second
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/stadium"
+)
+
+// maxStadiumBody bounds a stadium profile or patch
+const maxStadiumBody = 256 << 10
+
+// stadiumRoutes registers the stadium profile endpoints behind RequireAdmin
+func (s *Server) stadiumRoutes(mux *http.ServeMux) {
+	admin := RequireAdmin(s.config.Admin)
+	handle := func(pattern string, h http.HandlerFunc) {
+		mux.Handle(pattern, admin(h))
+	}
+
+	handle("GET /api/admin/stadiums", s.handleListStadiums)
+	handle("POST /api/admin/stadiums", s.handleCreateStadium)
+	handle("GET /api/admin/stadiums/{slug}", s.handleGetStadium)
+	handle("PUT /api/admin/stadiums/{slug}", s.handleUpdateStadium)
+	handle("PATCH /api/admin/stadiums/{slug}", s.handlePatchStadium)
+	handle("DELETE /api/admin/stadiums/{slug}", s.handleDeleteStadium)
+	handle("GET /api/admin/stadiums/{slug}/versions", s.handleStadiumVersions)
+	handle("GET /api/admin/stadiums/{slug}/versions/{version}", s.handleStadiumVersion)
+	handle("POST /api/admin/stadiums/{slug}/versions/{version}/restore", s.handleRestoreStadium)
+}
+
+func (s *Server) handleListStadiums(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"stadiums": s.stadiums.List(),
+	})
+}
+
+func (s *Server) handleGetStadium(w http.ResponseWriter, r *http.Request) {
+	profile, ok := s.stadiums.Get(r.PathValue("slug"))
+	if !ok {
+		http.Error(w, "Stadium not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, profile)
+}
+
+func (s *Server) handleCreateStadium(w http.ResponseWriter, r *http.Request) {
+	profile, ok := decodeStadium(w, r)
+	if !ok {
+		return
+	}
+	created, err := s.stadiums.Create(profile, adminActor(r))
+	if err != nil {
+		writeStadiumError(w, r, err)
+		return
+	}
+	w.Header().Set("Location", "/api/admin/stadiums/"+created.Slug)
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) handleUpdateStadium(w http.ResponseWriter, r *http.Request) {
+	profile, ok := decodeStadium(w, r)
+	if !ok {
+		return
+	}
+	updated, err := s.stadiums.Update(r.PathValue("slug"), profile, adminActor(r))
+	if err != nil {
+		writeStadiumError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+func (s *Server) handlePatchStadium(w http.ResponseWriter, r *http.Request) {
+	patch, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxStadiumBody))
+	if err != nil {
+		http.Error(w, "Invalid body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	updated, err := s.stadiums.Patch(r.PathValue("slug"), patch, adminActor(r))
+	if err != nil {
+		writeStadiumError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+func (s *Server) handleDeleteStadium(w http.ResponseWriter, r *http.Request) {
+	if err := s.stadiums.Delete(r.PathValue("slug"), adminActor(r)); err != nil {
+		writeStadiumError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleStadiumVersions(w http.ResponseWriter, r *http.Request) {
+	versions, ok := s.stadiums.Versions(r.PathValue("slug"))
+	if !ok {
+		http.Error(w, "Stadium not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"versions": versions,
+	})
+}
+
+func (s *Server) handleStadiumVersion(w http.ResponseWriter, r *http.Request) {
+	number, ok := stadiumVersion(w, r)
+	if !ok {
+		return
+	}
+	version, found := s.stadiums.Version(r.PathValue("slug"), number)
+	if !found {
+		http.Error(w, "Version not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, version)
+}
+
+func (s *Server) handleRestoreStadium(w http.ResponseWriter, r *http.Request) {
+	number, ok := stadiumVersion(w, r)
+	if !ok {
+		return
+	}
+	restored, err := s.stadiums.Restore(r.PathValue("slug"), number, adminActor(r))
+	if err != nil {
+		writeStadiumError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, restored)
+}
+
+func stadiumVersion(w http.ResponseWriter, r *http.Request) (int, bool) {
+	number, err := strconv.Atoi(r.PathValue("version"))
+	if err != nil || number <= 0 {
+		http.Error(w, "Invalid version", http.StatusBadRequest)
+		return 0, false
+	}
+	return number, true
+}
+
+func decodeStadium(w http.ResponseWriter, r *http.Request) (stadium.Profile, bool) {
+	var profile stadium.Profile
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxStadiumBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&profile); err != nil {
+		http.Error(w, "Invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return profile, false
+	}
+	return profile, true
+}
+
+func writeStadiumError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, stadium.ErrInvalid):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, stadium.ErrNotFound):
+		http.Error(w, "Stadium not found", http.StatusNotFound)
+	case errors.Is(err, stadium.ErrExists), errors.Is(err, stadium.ErrConflict), errors.Is(err, stadium.ErrProtected):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("[%s] Error saving stadium profile: %v", RequestIDFrom(r.Context()), err)
+		http.Error(w, "Failed to save stadium profile", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// hashedAsset matches build outputs with a content hash in the name, like
+// static/js/main.491316c5.js, which never change and can be cached forever
+var hashedAsset = regexp.MustCompile(`\.[0-9a-f]{8,}\.`)
+
+// precompressed lists the encodings build files may have variants for,
+// in order of preference
+var precompressed = []struct {
+	encoding  string
+	extension string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// staticHandler serves the frontend build from fsys. Unknown paths without
+// a file extension fall back to index.html so client-side routes work.
+// In dev mode files are read from disk and may change, so nothing is
+// cached.
+type staticHandler struct {
+	fsys  fs.FS
+	dev   bool
+	etags sync.Map // name -> ETag
+}
+
+func newStaticHandler(fsys fs.FS, dev bool) *staticHandler {
+	return &staticHandler{fsys: fsys, dev: dev}
+}
+
+func (h *staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" {
+		name = "index.html"
+	}
+
+	if !h.isFile(name) {
+		// Missing assets and API paths are real 404s; anything else is a
+		// client-side route
+		if strings.HasPrefix(name, "api/") || (path.Ext(name) != "" && name != "index.html") {
+			http.NotFound(w, r)
+			return
+		}
+		name = "index.html"
+	}
+	if name == "index.html" && !h.isFile(name) {
+		http.Error(w, "The frontend hasn't been built: run npm run build in frontend and rebuild the server", http.StatusServiceUnavailable)
+		return
+	}
+
+	if name == "index.html" || h.dev {
+		w.Header().Set("Cache-Control", "no-cache")
+	} else if hashedAsset.MatchString(path.Base(name)) {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+	}
+
+	h.serveFile(w, r, name)
+}
+
+func (h *staticHandler) isFile(name string) bool {
+	info, err := fs.Stat(h.fsys, name)
+	return err == nil && !info.IsDir()
+}
+
+// serveFile sends name, or a precompressed variant of it if the client
+// accepts one
+func (h *staticHandler) serveFile(w http.ResponseWriter, r *http.Request, name string) {
+	w.Header().Add("Vary", "Accept-Encoding")
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+
+	served := name
+	for _, variant := range precompressed {
+		if acceptsEncoding(r, variant.encoding) && h.isFile(name+variant.extension) {
+			served = name + variant.extension
+			w.Header().Set("Content-Encoding", variant.encoding)
+			break
+		}
+	}
+
+	data, err := fs.ReadFile(h.fsys, served)
+	if err != nil {
+		http.Error(w, "Error reading file", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("ETag", h.etag(served, data))
+
+	// ServeContent handles Range and If-None-Match; embedded files have no
+	// modification time, so Last-Modified is left out
+	var modTime time.Time
+	if info, err := fs.Stat(h.fsys, served); err == nil {
+		modTime = info.ModTime()
+	}
+	http.ServeContent(w, r, name, modTime, bytes.NewReader(data))
+}
+
+// etag returns a strong ETag for a file, computed once per file. Each
+// encoding is a separate file, so variants get distinct tags.
+func (h *staticHandler) etag(name string, data []byte) string {
+	if etag, ok := h.etags.Load(name); ok && !h.dev {
+		return etag.(string)
+	}
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	h.etags.Store(name, etag)
+	return etag
+}
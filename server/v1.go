@@ -0,0 +1,339 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/fotmob"
+	"github.com/MichaelBabushkin/sammy_po/pkg/i18n"
+	"github.com/MichaelBabushkin/sammy_po/pkg/schedule"
+	"github.com/MichaelBabushkin/sammy_po/pkg/scraper"
+	"github.com/MichaelBabushkin/sammy_po/pkg/transit"
+)
+
+const (
+	defaultPerPage = 20
+	maxPerPage     = 100
+)
+
+var (
+	// legacyDeprecatedAt is when /api/v1 replaced the unversioned routes
+	legacyDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	// legacySunset is when the unversioned routes may be removed
+	legacySunset = time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+)
+
+// v1Routes registers the versioned API. Its response shapes only change in
+// backwards compatible ways; anything else gets a new version.
+func (s *Server) v1Routes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v1/health", s.handleV1Health)
+	mux.HandleFunc("GET /api/v1/matches", s.handleV1Matches)
+	mux.HandleFunc("GET /api/v1/matches/{id}", s.handleV1Match)
+	mux.HandleFunc("GET /api/v1/matches/{id}/h2h", s.handleV1HeadToHead)
+	mux.HandleFunc("GET /api/v1/matches/{id}/transit", s.handleV1MatchTransit)
+	mux.HandleFunc("GET /api/v1/competitions", s.handleV1Competitions)
+	mux.HandleFunc("GET /api/v1/stadium", s.handleV1Stadium)
+	mux.HandleFunc("GET /api/v1/stadium/busy", s.handleV1StadiumBusy)
+	mux.HandleFunc("GET /api/v1/stadium/busy.ics", s.handleStadiumBusyICS)
+	mux.HandleFunc("GET /api/v1/venues/{slug}/conflicts", s.handleV1VenueConflicts)
+	mux.Handle("POST /api/v1/token/refresh", requireAdminV1(s.config.Admin)(http.HandlerFunc(s.handleV1RefreshToken)))
+
+	// Unknown v1 paths get an envelope rather than the SPA fallback
+	mux.HandleFunc("GET /api/v1/", func(w http.ResponseWriter, r *http.Request) {
+		writeEnvelopeError(w, http.StatusNotFound, CodeNotFound, "No such endpoint: "+r.Method+" "+r.URL.Path)
+	})
+}
+
+// Deprecated marks a legacy route with Deprecation and Sunset headers
+// (RFC 9745, RFC 8594) and links to its successor. {name} segments in
+// successor are filled from the request's path values.
+func Deprecated(successor string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set("Deprecation", fmt.Sprintf("@%d", legacyDeprecatedAt.Unix()))
+			h.Set("Sunset", legacySunset.Format(http.TimeFormat))
+			h.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, fillPathValues(successor, r)))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// fillPathValues replaces {name} segments of a route with r's path values
+func fillPathValues(route string, r *http.Request) string {
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = r.PathValue(strings.Trim(segment, "{}"))
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// MatchSummary is an upcoming match in the v1 list. Unlike the legacy
+// endpoint it only carries our own fields, never raw Fotmob ones.
+type MatchSummary struct {
+	fotmob.Match
+	Localized fotmob.LocalizedMatch `json:"localized"`
+}
+
+// pageParams reads ?page and ?perPage
+func pageParams(r *http.Request) (page, perPage int, err error) {
+	page, perPage = 1, defaultPerPage
+	if v := r.URL.Query().Get("page"); v != "" {
+		if page, err = strconv.Atoi(v); err != nil || page < 1 {
+			return 0, 0, fmt.Errorf("page must be a positive integer")
+		}
+	}
+	if v := r.URL.Query().Get("perPage"); v != "" {
+		if perPage, err = strconv.Atoi(v); err != nil || perPage < 1 || perPage > maxPerPage {
+			return 0, 0, fmt.Errorf("perPage must be between 1 and %d", maxPerPage)
+		}
+	}
+	return page, perPage, nil
+}
+
+func (s *Server) handleV1Health(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, Envelope{Data: s.health()})
+}
+
+// handleV1Competitions lists the competitions the fixture providers carry
+func (s *Server) handleV1Competitions(w http.ResponseWriter, r *http.Request) {
+	competitions, err := s.fixtures.Competitions(r.Context())
+	if err != nil {
+		s.writeEnvelopeUpstreamError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, Envelope{Data: competitions})
+}
+
+func (s *Server) handleV1Matches(w http.ResponseWriter, r *http.Request) {
+	page, perPage, err := pageParams(r)
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, CodeInvalidParameter, err.Error())
+		return
+	}
+
+	locale := i18n.FromRequest(r)
+	i18n.SetHeaders(w, locale)
+
+	matches, source, err := s.upcomingMatches(r.Context(), locale)
+	if err != nil {
+		s.writeEnvelopeUpstreamError(w, err)
+		return
+	}
+
+	summaries := make([]MatchSummary, 0, len(matches))
+	for _, raw := range matches {
+		matchMap, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		match, ok := matchMap["match"].(fotmob.Match)
+		if !ok {
+			continue
+		}
+		localized, _ := matchMap["localized"].(fotmob.LocalizedMatch)
+		summaries = append(summaries, MatchSummary{Match: match, Localized: localized})
+	}
+
+	total := len(summaries)
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+
+	meta := newMeta(source, locale)
+	meta.Pagination = &Pagination{
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: (total + perPage - 1) / perPage,
+	}
+	s.writeEnvelope(w, r, summaries[start:end], source, meta)
+}
+
+func (s *Server) handleV1Match(w http.ResponseWriter, r *http.Request) {
+	matchID, ok := matchIDParam(r)
+	if !ok {
+		writeEnvelopeError(w, http.StatusBadRequest, CodeInvalidParameter, "Invalid match ID")
+		return
+	}
+	locale := i18n.FromRequest(r)
+
+	details, source, err := s.matchDetails(r.Context(), matchID)
+	if err != nil {
+		s.writeEnvelopeUpstreamError(w, err)
+		return
+	}
+
+	i18n.SetHeaders(w, locale)
+	s.writeEnvelope(w, r, details.Localize(locale), source, newMeta(source, locale))
+}
+
+func (s *Server) handleV1HeadToHead(w http.ResponseWriter, r *http.Request) {
+	matchID, ok := matchIDParam(r)
+	if !ok {
+		writeEnvelopeError(w, http.StatusBadRequest, CodeInvalidParameter, "Invalid match ID")
+		return
+	}
+	locale := i18n.FromRequest(r)
+
+	h2h, source, err := s.headToHead(r.Context(), matchID)
+	if err != nil {
+		s.writeEnvelopeUpstreamError(w, err)
+		return
+	}
+
+	i18n.SetHeaders(w, locale)
+	s.writeEnvelope(w, r, h2h.Localize(locale), source, newMeta(source, locale))
+}
+
+func (s *Server) handleV1Stadium(w http.ResponseWriter, r *http.Request) {
+	locale := i18n.FromRequest(r)
+	i18n.SetHeaders(w, locale)
+
+	info, source := s.stadiumInfo(locale)
+	s.writeEnvelope(w, r, info, source, newMeta(source, locale))
+}
+
+// BusyCalendar is when the streets around the stadium are busy with match
+// crowds, earliest first
+type BusyCalendar struct {
+	Venue    string                `json:"venue"`
+	Address  string                `json:"address"`
+	Capacity int                   `json:"capacity"`
+	Windows  []schedule.BusyWindow `json:"windows"`
+}
+
+func (s *Server) handleV1StadiumBusy(w http.ResponseWriter, r *http.Request) {
+	locale := i18n.FromRequest(r)
+
+	windows, source, err := s.busyWindows(r.Context(), locale)
+	if err != nil {
+		s.writeEnvelopeUpstreamError(w, err)
+		return
+	}
+
+	info := s.sammyOfer().Info(locale)
+	i18n.SetHeaders(w, locale)
+	s.writeEnvelope(w, r, BusyCalendar{
+		Venue:    info.Name,
+		Address:  info.Address,
+		Capacity: s.occupancyModel().Capacity,
+		Windows:  windows,
+	}, source, newMeta(source, locale))
+}
+
+// handleStadiumBusyICS serves the busy windows as an iCalendar free/busy
+// feed for calendar subscriptions
+func (s *Server) handleStadiumBusyICS(w http.ResponseWriter, r *http.Request) {
+	locale := i18n.FromRequest(r)
+
+	windows, source, err := s.busyWindows(r.Context(), locale)
+	if err != nil {
+		s.writeUpstreamError(w, err)
+		return
+	}
+
+	i18n.SetHeaders(w, locale)
+	if source.Stale {
+		setStaleHeaders(w, source.FetchedAt)
+		source.MaxAge = 0
+	}
+	w.Header().Set("Content-Disposition", `inline; filename="sammyofer-busy.ics"`)
+	info := s.sammyOfer().Info(locale)
+	body := schedule.FreeBusyICS(info.Slug, info.Name, info.Address, windows, source.FetchedAt, locale)
+	s.writeValidated(w, r, body, "text/calendar; charset=utf-8", source.FetchedAt, source.MaxAge)
+}
+
+// MatchTransit is how to get to and from a match by public transport. The
+// times are null while the kickoff time is to be confirmed, and only the
+// nearby stops are given.
+type MatchTransit struct {
+	Match        fotmob.Match `json:"match"`
+	Venue        string       `json:"venue"`
+	GatesOpen    *time.Time   `json:"gatesOpen"`
+	ArriveBy     *time.Time   `json:"arriveBy"`
+	FinalWhistle *time.Time   `json:"finalWhistle"`
+	transit.Guidance
+}
+
+func (s *Server) handleV1MatchTransit(w http.ResponseWriter, r *http.Request) {
+	matchID, ok := matchIDParam(r)
+	if !ok {
+		writeEnvelopeError(w, http.StatusBadRequest, CodeInvalidParameter, "Invalid match ID")
+		return
+	}
+	locale := i18n.FromRequest(r)
+
+	guidance, source, err := s.matchTransit(r.Context(), matchID, locale)
+	switch {
+	case err == errNotAtStadium:
+		writeEnvelopeError(w, http.StatusNotFound, CodeNotFound, "No upcoming match at Sammy Ofer with ID "+r.PathValue("id"))
+		return
+	case errors.Is(err, errTransitUnavailable):
+		log.Printf("[%s] %v", RequestIDFrom(r.Context()), err)
+		writeEnvelopeError(w, http.StatusServiceUnavailable, CodeUpstreamUnavailable, "Transit suggestions are unavailable")
+		return
+	case err != nil:
+		s.writeEnvelopeUpstreamError(w, err)
+		return
+	}
+
+	i18n.SetHeaders(w, locale)
+	s.writeEnvelope(w, r, guidance, source, newMeta(source, locale))
+}
+
+func (s *Server) handleV1VenueConflicts(w http.ResponseWriter, r *http.Request) {
+	venue, window, err := s.conflictParams(r)
+	if err == errUnknownVenue {
+		writeEnvelopeError(w, http.StatusNotFound, CodeNotFound, "Unknown venue: "+r.PathValue("slug"))
+		return
+	}
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadRequest, CodeInvalidParameter, err.Error())
+		return
+	}
+	locale := i18n.FromRequest(r)
+
+	report, source, err := s.venueConflicts(r.Context(), venue, window, locale)
+	if err != nil {
+		s.writeEnvelopeUpstreamError(w, err)
+		return
+	}
+
+	i18n.SetHeaders(w, locale)
+	s.writeEnvelope(w, r, report, source, newMeta(source, locale))
+}
+
+func (s *Server) handleV1RefreshToken(w http.ResponseWriter, r *http.Request) {
+	log.Println("Received request to manually refresh token")
+
+	token, source, err := s.manualRefresh(r)
+	if denied, ok := err.(*refreshDenied); ok {
+		writeEnvelopeRetry(w, http.StatusTooManyRequests, CodeRateLimited, denied.reason, denied.wait)
+		return
+	}
+	if err != nil {
+		writeEnvelopeError(w, http.StatusBadGateway, CodeRefreshFailed, fmt.Sprintf("Failed to refresh token: %v", err))
+		return
+	}
+
+	refreshedAt := time.Now().UTC().Truncate(time.Second)
+	writeJSON(w, http.StatusOK, Envelope{Data: map[string]interface{}{
+		"tokenPreview": scraper.TruncateToken(token),
+		"source":       source,
+		"refreshedAt":  refreshedAt,
+	}})
+}
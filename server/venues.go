@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/MichaelBabushkin/sammy_po/pkg/fotmob"
+	"github.com/MichaelBabushkin/sammy_po/pkg/outbound"
+)
+
+// venueLookupInterval is the minimum time between two batches of venue
+// lookups
+const venueLookupInterval = 10 * time.Minute
+
+// venueLookups fetches match details in the background for upcoming
+// fixtures whose venue is only guessed from the home team, so the Sammy
+// Ofer filter can use the venue Fotmob gives instead. Lookups run a batch at
+// a time at background priority, and stop at the first failure.
+type venueLookups struct {
+	lookahead time.Duration
+	batch     int
+	fetch     func(ctx context.Context, matchID int) error
+
+	mu      sync.Mutex
+	running bool
+	last    time.Time
+}
+
+func newVenueLookups(lookahead time.Duration, batch int, client *fotmob.FotmobClient) *venueLookups {
+	return &venueLookups{
+		lookahead: lookahead,
+		batch:     batch,
+		fetch: func(ctx context.Context, matchID int) error {
+			_, err := fotmob.GetMatchDetails(ctx, client, matchID)
+			return err
+		},
+	}
+}
+
+// trigger starts a batch of lookups for fixtures, raw league matches from
+// Fotmob, unless one ran recently or lookups are disabled
+func (v *venueLookups) trigger(fixtures []interface{}) {
+	if v.batch <= 0 || v.lookahead <= 0 {
+		return
+	}
+
+	v.mu.Lock()
+	if v.running || time.Since(v.last) < venueLookupInterval {
+		v.mu.Unlock()
+		return
+	}
+	ids := fotmob.VenueCandidates(fixtures, v.lookahead)
+	if len(ids) == 0 {
+		v.mu.Unlock()
+		return
+	}
+	v.running = true
+	v.last = time.Now()
+	v.mu.Unlock()
+
+	if len(ids) > v.batch {
+		ids = ids[:v.batch]
+	}
+
+	go func() {
+		defer func() {
+			v.mu.Lock()
+			v.running = false
+			v.mu.Unlock()
+		}()
+
+		ctx := outbound.WithPriority(context.Background(), outbound.Background)
+		for i, id := range ids {
+			if err := v.fetch(ctx, id); err != nil {
+				log.Printf("Venue lookup stopped after %d of %d fixtures: match %d: %v", i, len(ids), id, err)
+				return
+			}
+		}
+		log.Printf("Looked up venues for %d upcoming fixtures", len(ids))
+	}()
+}